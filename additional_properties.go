@@ -2,6 +2,7 @@ package jsonschema
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -60,20 +61,19 @@ func evaluateAdditionalProperties(
 	}
 
 	if len(invalidProperties) == 1 {
-		return results, NewEvaluationError(
-			"additionalProperties", "additional_property_mismatch",
-			"Additional property {property} does not match the schema",
+		return results, newError(
+			"additionalProperties", CodeAdditionalPropertyMismatch,
 			map[string]any{"property": fmt.Sprintf("'%s'", invalidProperties[0])},
 		)
 	}
 	if len(invalidProperties) > 1 {
+		slices.Sort(invalidProperties)
 		quotedProperties := make([]string, len(invalidProperties))
 		for i, prop := range invalidProperties {
 			quotedProperties[i] = fmt.Sprintf("'%s'", prop)
 		}
-		return results, NewEvaluationError(
-			"additionalProperties", "additional_properties_mismatch",
-			"Additional properties {properties} do not match the schema",
+		return results, newError(
+			"additionalProperties", CodeAdditionalPropertiesMismatch,
 			map[string]any{"properties": strings.Join(quotedProperties, ", ")},
 		)
 	}
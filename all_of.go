@@ -53,7 +53,7 @@ func evaluateAllOf(
 		return results, nil
 	}
 
-	return results, NewEvaluationError("allOf", "all_of_item_mismatch", "Value does not match the allOf schema at index {indexs}", map[string]any{
+	return results, newError("allOf", CodeAllOfItemMismatch, map[string]any{
 		"indexs": strings.Join(invalidIndexes, ", "),
 	})
 }
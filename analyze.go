@@ -0,0 +1,206 @@
+package jsonschema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AnalysisCode identifies what Schema.Analyze found. Codes are stable and
+// safe to match on.
+type AnalysisCode string
+
+const (
+	// AnalysisEmptyNumberRange is a minimum or exclusiveMinimum above the
+	// maximum or exclusiveMaximum, so no number is valid.
+	AnalysisEmptyNumberRange AnalysisCode = "emptyNumberRange"
+	// AnalysisEmptyLengthRange is a minLength above maxLength, so no string is
+	// valid.
+	AnalysisEmptyLengthRange AnalysisCode = "emptyLengthRange"
+	// AnalysisEmptyItemsRange is a minItems above maxItems, so no array is
+	// valid.
+	AnalysisEmptyItemsRange AnalysisCode = "emptyItemsRange"
+	// AnalysisEmptyPropertiesRange is a minProperties above maxProperties, so
+	// no object is valid.
+	AnalysisEmptyPropertiesRange AnalysisCode = "emptyPropertiesRange"
+	// AnalysisConstTypeMismatch is a const value of none of the types listed
+	// by "type".
+	AnalysisConstTypeMismatch AnalysisCode = "constTypeMismatch"
+	// AnalysisConstNotInEnum is a const value missing from "enum".
+	AnalysisConstNotInEnum AnalysisCode = "constNotInEnum"
+	// AnalysisEmptyEnum is an "enum" without values.
+	AnalysisEmptyEnum AnalysisCode = "emptyEnum"
+	// AnalysisEnumTypeMismatch is an "enum" none of whose values is of the
+	// types listed by "type".
+	AnalysisEnumTypeMismatch AnalysisCode = "enumTypeMismatch"
+	// AnalysisDisjointAllOfTypes is an "allOf" whose branches, together with
+	// the schema itself, allow no common type.
+	AnalysisDisjointAllOfTypes AnalysisCode = "disjointAllOfTypes"
+	// AnalysisRequiredPropertyFalse is a required property whose schema is
+	// false.
+	AnalysisRequiredPropertyFalse AnalysisCode = "requiredPropertyFalse"
+	// AnalysisMinItemsExceedsTuple is a minItems above the number of items
+	// prefixItems allows when further items are forbidden.
+	AnalysisMinItemsExceedsTuple AnalysisCode = "minItemsExceedsTuple"
+)
+
+// AnalysisFinding is one subschema Schema.Analyze found unsatisfiable.
+type AnalysisFinding struct {
+	Code     AnalysisCode
+	Location string // JSON Pointer to the schema within the document.
+	Message  string
+}
+
+func (f AnalysisFinding) String() string {
+	return fmt.Sprintf("#%s: %s", f.Location, f.Message)
+}
+
+// Analyze reports the subschemas of s, s included, whose keywords contradict
+// each other:
+//
+//   - empty ranges: minimum above maximum, minLength above maxLength,
+//     minItems above maxItems and minProperties above maxProperties;
+//   - a const value of none of the listed types or missing from "enum";
+//   - an empty "enum", or one with no value of the listed types;
+//   - "allOf" branches whose "type" keywords have no type in common;
+//   - required properties whose schema is false;
+//   - a minItems above the length prefixItems allows when "items" is false.
+//
+// Range contradictions only rule out values of the type the keywords apply
+// to; the others make a schema reject every value. References are not
+// followed.
+func (s *Schema) Analyze() []AnalysisFinding {
+	var findings []AnalysisFinding
+	var visit func(s *Schema, location string)
+	visit = func(s *Schema, location string) {
+		if s.Boolean != nil {
+			return
+		}
+		findings = append(findings, analyzeSchema(s, location)...)
+		for _, child := range strictChildren(s, location) {
+			visit(child.schema, child.location)
+		}
+	}
+	visit(s, "")
+	return findings
+}
+
+// analyzeSchema returns the findings for the keywords of s alone.
+func analyzeSchema(s *Schema, location string) []AnalysisFinding {
+	var findings []AnalysisFinding
+	report := func(code AnalysisCode, format string, args ...any) {
+		findings = append(findings, AnalysisFinding{Code: code, Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if lower, upper, ok := emptyNumberRange(s); ok {
+		report(AnalysisEmptyNumberRange, "no number is both %s and %s", lower, upper)
+	}
+	for _, bounds := range []struct {
+		code             AnalysisCode
+		minimum, maximum *float64
+		minName, maxName string
+	}{
+		{AnalysisEmptyLengthRange, s.MinLength, s.MaxLength, "minLength", "maxLength"},
+		{AnalysisEmptyItemsRange, s.MinItems, s.MaxItems, "minItems", "maxItems"},
+		{AnalysisEmptyPropertiesRange, s.MinProperties, s.MaxProperties, "minProperties", "maxProperties"},
+	} {
+		if bounds.minimum != nil && bounds.maximum != nil && *bounds.minimum > *bounds.maximum {
+			report(bounds.code, "%s %v exceeds %s %v", bounds.minName, *bounds.minimum, bounds.maxName, *bounds.maximum)
+		}
+	}
+
+	hasConst := s.Const != nil && s.Const.IsSet
+	if hasConst && len(s.Type) > 0 && !valueHasType(s.Const.Value, s.Type) {
+		report(AnalysisConstTypeMismatch, "const is %s, but \"type\" is %s", getDataType(s.Const.Value), strings.Join(s.Type, ", "))
+	}
+	switch {
+	case s.Enum != nil && len(s.Enum) == 0:
+		report(AnalysisEmptyEnum, "enum has no values")
+	case hasConst && s.Enum != nil &&
+		!slices.ContainsFunc(s.Enum, func(value any) bool { return valuesEqual(value, s.Const.Value) }):
+		report(AnalysisConstNotInEnum, "const is not one of the enum values")
+	case len(s.Enum) > 0 && len(s.Type) > 0 &&
+		!slices.ContainsFunc(s.Enum, func(value any) bool { return valueHasType(value, s.Type) }):
+		report(AnalysisEnumTypeMismatch, "no enum value is %s", strings.Join(s.Type, ", "))
+	}
+
+	if len(s.AllOf) > 0 {
+		types, constrained := []string(nil), false
+		if len(s.Type) > 0 {
+			types, constrained = s.Type, true
+		}
+		for _, branch := range s.AllOf {
+			if branch == nil || branch.Boolean != nil || len(branch.Type) == 0 {
+				continue
+			}
+			if !constrained {
+				types, constrained = branch.Type, true
+				continue
+			}
+			types = intersectTypes(types, branch.Type)
+		}
+		if constrained && len(types) == 0 {
+			report(AnalysisDisjointAllOfTypes, "the types allowed by allOf have nothing in common")
+		}
+	}
+
+	properties := schemaMap(s.Properties)
+	for _, name := range s.Required {
+		if property := properties[name]; property != nil && property.Boolean != nil && !*property.Boolean {
+			report(AnalysisRequiredPropertyFalse, "required property %q has schema false", name)
+		}
+	}
+
+	if s.MinItems != nil && s.Items != nil && s.Items.Boolean != nil && !*s.Items.Boolean &&
+		*s.MinItems > float64(len(s.PrefixItems)) {
+		report(AnalysisMinItemsExceedsTuple, "minItems %v exceeds the %d items prefixItems allows", *s.MinItems, len(s.PrefixItems))
+	}
+	return findings
+}
+
+// emptyNumberRange reports whether the numeric bounds of s leave no valid
+// number, and describes the contradicting bounds.
+func emptyNumberRange(s *Schema) (string, string, bool) {
+	lower, lowerKeyword := s.Minimum, "minimum"
+	if s.ExclusiveMinimum != nil && (lower == nil || s.ExclusiveMinimum.Cmp(lower.Rat) >= 0) {
+		lower, lowerKeyword = s.ExclusiveMinimum, "exclusiveMinimum"
+	}
+	upper, upperKeyword := s.Maximum, "maximum"
+	if s.ExclusiveMaximum != nil && (upper == nil || s.ExclusiveMaximum.Cmp(upper.Rat) <= 0) {
+		upper, upperKeyword = s.ExclusiveMaximum, "exclusiveMaximum"
+	}
+	if lower == nil || upper == nil {
+		return "", "", false
+	}
+	comparison := lower.Cmp(upper.Rat)
+	exclusive := lowerKeyword == "exclusiveMinimum" || upperKeyword == "exclusiveMaximum"
+	if comparison < 0 || comparison == 0 && !exclusive {
+		return "", "", false
+	}
+	return fmt.Sprintf("%s %s", lowerKeyword, FormatRat(lower)), fmt.Sprintf("%s %s", upperKeyword, FormatRat(upper)), true
+}
+
+// valueHasType reports whether value is of one of types, integers counting
+// as numbers.
+func valueHasType(value any, types []string) bool {
+	dataType := getDataType(value)
+	return slices.ContainsFunc(types, func(t string) bool {
+		return t == dataType || t == "number" && dataType == "integer"
+	})
+}
+
+// intersectTypes returns the types both a and b allow.
+func intersectTypes(a, b []string) []string {
+	var common []string
+	for _, t := range a {
+		switch {
+		case slices.Contains(b, t):
+			common = append(common, t)
+		case t == "number" && slices.Contains(b, "integer"):
+			common = append(common, "integer")
+		case t == "integer" && slices.Contains(b, "number"):
+			common = append(common, "integer")
+		}
+	}
+	return common
+}
@@ -0,0 +1,78 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected []AnalysisFinding
+	}{
+		{
+			name:   "empty ranges",
+			schema: `{"minimum": 5, "maximum": 1, "properties": {"a": {"exclusiveMinimum": 2.5, "maximum": 2.5}, "b": {"minLength": 3, "maxLength": 2}, "c": {"minItems": 2, "maxItems": 1, "minProperties": 1, "maxProperties": 0}, "d": {"minimum": 1, "maximum": 1}}}`,
+			expected: []AnalysisFinding{
+				{Code: AnalysisEmptyNumberRange, Location: "", Message: "no number is both minimum 5 and maximum 1"},
+				{Code: AnalysisEmptyNumberRange, Location: "/properties/a", Message: "no number is both exclusiveMinimum 2.5 and maximum 2.5"},
+				{Code: AnalysisEmptyLengthRange, Location: "/properties/b", Message: "minLength 3 exceeds maxLength 2"},
+				{Code: AnalysisEmptyItemsRange, Location: "/properties/c", Message: "minItems 2 exceeds maxItems 1"},
+				{Code: AnalysisEmptyPropertiesRange, Location: "/properties/c", Message: "minProperties 1 exceeds maxProperties 0"},
+			},
+		},
+		{
+			name:   "const and enum",
+			schema: `{"anyOf": [{"type": "string", "const": 1}, {"const": "a", "enum": ["b"]}, {"enum": []}, {"type": "integer", "enum": ["1", 1.5]}, {"type": "number", "const": 2, "enum": [2, 3]}]}`,
+			expected: []AnalysisFinding{
+				{Code: AnalysisConstTypeMismatch, Location: "/anyOf/0", Message: `const is integer, but "type" is string`},
+				{Code: AnalysisConstNotInEnum, Location: "/anyOf/1", Message: "const is not one of the enum values"},
+				{Code: AnalysisEmptyEnum, Location: "/anyOf/2", Message: "enum has no values"},
+				{Code: AnalysisEnumTypeMismatch, Location: "/anyOf/3", Message: "no enum value is integer"},
+			},
+		},
+		{
+			name:   "disjoint allOf types",
+			schema: `{"$defs": {"a": {"allOf": [{"type": "string"}, {"type": ["integer", "null"]}]}, "b": {"type": "integer", "allOf": [{"type": "number"}, {"minimum": 1}]}, "c": {"type": "number", "allOf": [{"type": ["string", "null"]}]}}}`,
+			expected: []AnalysisFinding{
+				{Code: AnalysisDisjointAllOfTypes, Location: "/$defs/a", Message: "the types allowed by allOf have nothing in common"},
+				{Code: AnalysisDisjointAllOfTypes, Location: "/$defs/c", Message: "the types allowed by allOf have nothing in common"},
+			},
+		},
+		{
+			name:   "required property false",
+			schema: `{"properties": {"id": false, "name": false}, "required": ["id"]}`,
+			expected: []AnalysisFinding{
+				{Code: AnalysisRequiredPropertyFalse, Location: "", Message: `required property "id" has schema false`},
+			},
+		},
+		{
+			name:   "minItems exceeding a closed tuple",
+			schema: `{"prefixItems": [{}, {}], "items": false, "minItems": 3, "properties": {"open": {"prefixItems": [{}], "minItems": 3}}}`,
+			expected: []AnalysisFinding{
+				{Code: AnalysisMinItemsExceedsTuple, Location: "", Message: "minItems 3 exceeds the 2 items prefixItems allows"},
+			},
+		},
+		{
+			name:   "legacy dialects",
+			schema: `{"$schema": "http://json-schema.org/draft-04/schema#", "definitions": {"n": {"minimum": 1, "maximum": 1, "exclusiveMaximum": true}}}`,
+			expected: []AnalysisFinding{
+				{Code: AnalysisEmptyNumberRange, Location: "/definitions/n", Message: "no number is both minimum 1 and exclusiveMaximum 1"},
+			},
+		},
+		{
+			name:   "satisfiable",
+			schema: `{"type": "object", "properties": {"n": {"type": "integer", "const": 1, "enum": [1, 2], "minimum": 0, "exclusiveMaximum": 2}}, "required": ["n"]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewCompiler().Compile([]byte(tt.schema))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schema.Analyze())
+		})
+	}
+}
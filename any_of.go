@@ -50,5 +50,5 @@ func evaluateAnyOf(
 	if valid {
 		return results, nil // Return nil only if at least one schema succeeds
 	}
-	return results, NewEvaluationError("anyOf", "any_of_item_mismatch", "Value does not match anyOf schema")
+	return results, newError("anyOf", CodeAnyOfItemMismatch)
 }
@@ -0,0 +1,161 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// Bundle returns the schema document s belongs to with every externally
+// referenced resource embedded, following the JSON Schema 2020-12 bundling
+// procedure. Each resource is added under "$defs", keyed by its URI and
+// carrying that URI as "$id", so the "$ref" values are left unchanged and
+// resolve to the embedded copies. Documents are embedded as they were
+// compiled, keeping their keywords and "$schema", and a resource gets a
+// "$schema" when its dialect differs from the bundle's, so mixed-dialect
+// bundles validate as before with any implementation. Draft-04 and Draft-03
+// resources carry their URI as "id" instead.
+//
+// Bundle fails with ErrUnresolvedReference when a reference cannot be
+// resolved.
+func (s *Schema) Bundle() ([]byte, error) {
+	root := s.rootSchema()
+	resources, err := externalResources(root)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := documentObject(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return json.Marshal(document, json.Deterministic(true))
+	}
+
+	defs := make(map[string]jsontext.Value)
+	if raw, ok := document["$defs"]; ok {
+		if err := json.Unmarshal(raw, &defs); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+		}
+	}
+
+	for _, resource := range resources {
+		embedded, err := documentObject(resource.schema)
+		if err != nil {
+			return nil, err
+		}
+		idKeyword := "$id"
+		if dialect := resource.schema.Dialect(); dialect == Draft4 || dialect == Draft3 {
+			idKeyword = "id"
+		}
+		embedded[idKeyword], _ = json.Marshal(resource.uri)
+		if _, ok := embedded["$schema"]; !ok && resource.schema.Dialect() != root.Dialect() {
+			embedded["$schema"], _ = json.Marshal(string(resource.schema.Dialect()))
+		}
+		value, err := json.Marshal(embedded, json.Deterministic(true))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+		}
+
+		key := resource.uri
+		for i := 2; defs[key] != nil; i++ {
+			key = fmt.Sprintf("%s~%d", resource.uri, i)
+		}
+		defs[key] = value
+	}
+
+	document["$defs"], err = json.Marshal(defs, json.Deterministic(true))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+	}
+	return json.Marshal(document, json.Deterministic(true))
+}
+
+// Bundle returns the schema at uri bundled with the resources it references,
+// see Schema.Bundle.
+func (c *Compiler) Bundle(uri string) ([]byte, error) {
+	schema, err := c.Schema(uri)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnresolvedReference, uri)
+	}
+	return schema.Bundle()
+}
+
+// bundledResource is a document embedded by Bundle.
+type bundledResource struct {
+	schema *Schema
+	uri    string
+}
+
+// externalResources returns the documents root references, directly or
+// through other documents, in the order they are first referenced.
+func externalResources(root *Schema) ([]bundledResource, error) {
+	var resources []bundledResource
+	seen := map[*Schema]bool{root: true}
+	var unresolved []string
+
+	var visit func(*Schema)
+	visit = func(s *Schema) {
+		for _, ref := range []struct {
+			ref      string
+			resolved *Schema
+		}{{s.Ref, s.ResolvedRef}, {s.DynamicRef, s.ResolvedDynamicRef}} {
+			if ref.ref == "" {
+				continue
+			}
+			if ref.resolved == nil {
+				unresolved = append(unresolved, ref.ref)
+				continue
+			}
+			if document := ref.resolved.rootSchema(); !seen[document] {
+				seen[document] = true
+				// Schemas added with SetSchema may not know their URI.
+				uri := document.uri
+				if uri == "" {
+					uri = s.unresolvedReferenceTargetURI(ref.ref)
+				}
+				resources = append(resources, bundledResource{schema: document, uri: uri})
+				visit(document)
+			}
+		}
+		s.walkNestedSchemas(visit)
+	}
+	visit(root)
+
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnresolvedReference, strings.Join(unresolved, ", "))
+	}
+	return resources, nil
+}
+
+// documentObject returns the keywords of the document s was compiled from,
+// or of s marshaled when it was built otherwise, such as with SetSchema.
+func documentObject(s *Schema) (map[string]jsontext.Value, error) {
+	if s.source == nil {
+		return schemaObject(s)
+	}
+	object := make(map[string]jsontext.Value)
+	if err := json.Unmarshal(s.source, &object); err != nil {
+		return nil, fmt.Errorf("%w: %s is not an object schema", ErrInvalidSchemaType, s.uri)
+	}
+	return object, nil
+}
+
+// schemaObject marshals s into its keywords.
+func schemaObject(s *Schema) (map[string]jsontext.Value, error) {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+	}
+	object := make(map[string]jsontext.Value)
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("%w: %s is not an object schema", ErrInvalidSchemaType, s.uri)
+	}
+	return object, nil
+}
@@ -0,0 +1,127 @@
+package jsonschema
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-json-experiment/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBundleCompiler() *Compiler {
+	return NewCompiler().RegisterFS("https://example.com/schemas/", fstest.MapFS{
+		"user.json": {Data: []byte(`{
+			"$id": "https://example.com/schemas/user.json",
+			"type": "object",
+			"properties": {
+				"name": {"$ref": "name.json"},
+				"address": {"$ref": "common/address.json#/$defs/address"},
+				"legacy": {"$ref": "legacy.json"}
+			},
+			"$defs": {"local": {"type": "boolean"}}
+		}`)},
+		"name.json":           {Data: []byte(`{"type": "string", "minLength": 2}`)},
+		"common/address.json": {Data: []byte(`{"$defs": {"address": {"type": "object", "properties": {"zip": {"$ref": "../zip.json"}}, "required": ["zip"]}}}`)},
+		"zip.json":            {Data: []byte(`{"type": "string", "pattern": "^[0-9]{5}$"}`)},
+		"legacy.json":         {Data: []byte(`{"$schema": "http://json-schema.org/draft-07/schema#", "items": [{"type": "integer"}], "additionalItems": false}`)},
+	})
+}
+
+func TestBundleEmbedsExternalResources(t *testing.T) {
+	bundled, err := newBundleCompiler().Bundle("https://example.com/schemas/user.json")
+	require.NoError(t, err)
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(bundled, &document))
+	defs := document["$defs"].(map[string]any)
+	assert.Contains(t, defs, "local", "existing definitions are kept")
+	for _, uri := range []string{
+		"https://example.com/schemas/name.json",
+		"https://example.com/schemas/common/address.json",
+		"https://example.com/schemas/zip.json",
+		"https://example.com/schemas/legacy.json",
+	} {
+		require.Contains(t, defs, uri)
+		assert.Equal(t, uri, defs[uri].(map[string]any)["$id"])
+	}
+	assert.Equal(t, string(Draft7), defs["https://example.com/schemas/legacy.json"].(map[string]any)["$schema"])
+
+	properties := document["properties"].(map[string]any)
+	assert.Equal(t, "name.json", properties["name"].(map[string]any)["$ref"], "$ref values are unchanged")
+}
+
+func TestBundleValidatesLikeTheOriginal(t *testing.T) {
+	original, err := newBundleCompiler().Schema("https://example.com/schemas/user.json")
+	require.NoError(t, err)
+	bundled, err := original.Bundle()
+	require.NoError(t, err)
+
+	offline := NewCompiler()
+	delete(offline.Loaders, "https")
+	schema, err := offline.Compile(bundled)
+	require.NoError(t, err)
+	require.Empty(t, schema.UnresolvedReferenceURIs())
+
+	tests := []struct {
+		instance any
+		valid    bool
+	}{
+		{map[string]any{"name": "Al", "address": map[string]any{"zip": "12345"}, "legacy": []any{1}}, true},
+		{map[string]any{"name": "A"}, false},
+		{map[string]any{"address": map[string]any{"zip": "abc"}}, false},
+		{map[string]any{"address": map[string]any{}}, false},
+		{map[string]any{"legacy": []any{1, 2}}, false},
+		{map[string]any{"legacy": []any{"one"}}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.valid, original.Validate(tt.instance).IsValid(), "original: %v", tt.instance)
+		assert.Equal(t, tt.valid, schema.Validate(tt.instance).IsValid(), "bundled: %v", tt.instance)
+	}
+}
+
+func TestBundleEmbedsSourceDocuments(t *testing.T) {
+	legacy := `{"$schema": "http://json-schema.org/draft-04/schema#", "type": "array", "items": [{"type": "integer", "minimum": 0, "exclusiveMinimum": true}], "additionalItems": false, "dependencies": {"a": ["b"]}}`
+	compiler := NewCompiler().RegisterFS("https://example.com/schemas/", fstest.MapFS{
+		"legacy.json": {Data: []byte(legacy)},
+	})
+	original, err := compiler.Compile([]byte(`{"$id": "https://example.com/schemas/root.json", "$ref": "legacy.json"}`))
+	require.NoError(t, err)
+	bundled, err := original.Bundle()
+	require.NoError(t, err)
+
+	var document struct {
+		Defs map[string]map[string]any `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(bundled, &document))
+	embedded := document.Defs["https://example.com/schemas/legacy.json"]
+	var expected map[string]any
+	require.NoError(t, json.Unmarshal([]byte(legacy), &expected))
+	expected["id"] = "https://example.com/schemas/legacy.json"
+	assert.Equal(t, expected, embedded, "Draft-04 keywords are embedded as written")
+
+	offline, err := NewCompiler().Compile(bundled)
+	require.NoError(t, err)
+	for _, instance := range []any{[]any{1}, []any{0}, []any{1, 2}, []any{"a"}} {
+		assert.Equal(t, original.Validate(instance).IsValid(), offline.Validate(instance).IsValid(), "%v", instance)
+	}
+}
+
+func TestBundleWithoutExternalReferences(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"$defs": {"n": {"type": "integer"}}, "$ref": "#/$defs/n"}`))
+	require.NoError(t, err)
+
+	bundled, err := schema.Bundle()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"$defs": {"n": {"type": "integer"}}, "$ref": "#/$defs/n"}`, string(bundled))
+}
+
+func TestBundleUnresolvedReference(t *testing.T) {
+	compiler := NewCompiler()
+	delete(compiler.Loaders, "https")
+	schema, err := compiler.Compile([]byte(`{"$ref": "https://example.com/missing.json"}`))
+	require.NoError(t, err)
+
+	_, err = schema.Bundle()
+	require.ErrorIs(t, err, ErrUnresolvedReference)
+}
@@ -0,0 +1,263 @@
+package jsonschema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachingLoaderOptions configures NewCachingLoader.
+type CachingLoaderOptions struct {
+	// Dir is the directory the fetched documents are stored in. It is
+	// created on the first store.
+	Dir string
+
+	// Offline serves documents from Dir only. A URL missing from the cache
+	// fails with ErrNetworkDisabled; stale entries are served as they are.
+	Offline bool
+
+	// HTTP is the policy for fetching and revalidating documents. Setting
+	// HTTP.DisableNetwork has the same effect as Offline.
+	HTTP HTTPLoaderOptions
+}
+
+// CachingLoaderStats counts how a CachingLoader served its documents.
+type CachingLoaderStats struct {
+	// Hits counts documents served from the cache without a request.
+	Hits int64 `json:"hits"`
+	// Revalidations counts cached documents confirmed with a 304 response.
+	Revalidations int64 `json:"revalidations"`
+	// Misses counts documents downloaded because they were not cached or
+	// had changed.
+	Misses int64 `json:"misses"`
+}
+
+// CachingLoader is a loader for "http" and "https" URLs that keeps the
+// fetched documents on disk, keyed by URL, so other processes and later runs
+// do not fetch them again. Entries are fresh for the lifetime given by the
+// Cache-Control max-age or Expires response headers. Stale entries are
+// revalidated with If-None-Match and If-Modified-Since using the stored ETag
+// and Last-Modified values. Responses with Cache-Control no-store are not
+// cached.
+//
+//	cache := jsonschema.NewCachingLoader(jsonschema.CachingLoaderOptions{Dir: ".schema-cache"})
+//	compiler.RegisterLoader("http", cache.Load).RegisterLoader("https", cache.Load)
+type CachingLoader struct {
+	dir     string
+	offline bool
+	opts    HTTPLoaderOptions
+	client  *http.Client
+	now     func() time.Time
+
+	mu    sync.Mutex // guards stats and the cache files, not the requests
+	stats CachingLoaderStats
+}
+
+// cacheEntry is the metadata stored next to a cached document.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Expires      time.Time `json:"expires,omitzero"`
+	NoCache      bool      `json:"noCache,omitempty"`
+	ContentType  string    `json:"contentType,omitempty"`
+}
+
+// NewCachingLoader creates a CachingLoader storing documents in opts.Dir.
+func NewCachingLoader(opts CachingLoaderOptions) *CachingLoader {
+	return &CachingLoader{
+		dir:     opts.Dir,
+		offline: opts.Offline || opts.HTTP.DisableNetwork,
+		opts:    opts.HTTP,
+		client:  opts.HTTP.client(),
+		now:     time.Now,
+	}
+}
+
+// Stats returns the counters accumulated since the loader was created.
+func (l *CachingLoader) Stats() CachingLoaderStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// Load returns the document at rawURL from the cache, revalidating or
+// downloading it when needed. A fetched document is returned even when it
+// cannot be stored.
+func (l *CachingLoader) Load(rawURL string) (io.ReadCloser, error) {
+	l.mu.Lock()
+	entry, data, cached := l.read(rawURL)
+	hit := cached && (l.offline || l.fresh(entry))
+	if hit {
+		l.stats.Hits++
+	}
+	l.mu.Unlock()
+	switch {
+	case hit:
+		return entry.body(data), nil
+	case l.offline:
+		return nil, fmt.Errorf("%w: %s is not cached", ErrNetworkDisabled, rawURL)
+	}
+
+	header := http.Header{}
+	if cached && entry.ETag != "" {
+		header.Set("If-None-Match", entry.ETag)
+	}
+	if cached && entry.LastModified != "" {
+		header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	resp, err := l.opts.get(l.client, rawURL, header)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified {
+		l.updateEntry(&entry, resp.Header)
+		l.mu.Lock()
+		l.stats.Revalidations++
+		_ = l.writeEntry(entry, nil)
+		l.mu.Unlock()
+		return entry.body(data), nil
+	}
+
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDataRead, err)
+	}
+	entry = cacheEntry{URL: rawURL}
+	l.updateEntry(&entry, resp.Header)
+	l.mu.Lock()
+	l.stats.Misses++
+	if !hasDirective(resp.Header, "no-store") {
+		_ = l.writeEntry(entry, data)
+	}
+	l.mu.Unlock()
+	return entry.body(data), nil
+}
+
+// body returns the cached document with its media type.
+func (entry cacheEntry) body(data []byte) io.ReadCloser {
+	return typedBody{ReadCloser: io.NopCloser(bytes.NewReader(data)), contentType: entry.ContentType}
+}
+
+// fresh reports whether entry can be served without revalidation.
+func (l *CachingLoader) fresh(entry cacheEntry) bool {
+	return !entry.NoCache && l.now().Before(entry.Expires)
+}
+
+// updateEntry records the validators and freshness lifetime of a response.
+// A 304 response only carries the headers that changed.
+func (l *CachingLoader) updateEntry(entry *cacheEntry, header http.Header) {
+	if etag := header.Get("ETag"); etag != "" {
+		entry.ETag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		entry.LastModified = lastModified
+	}
+	if contentType := header.Get("Content-Type"); contentType != "" {
+		entry.ContentType = contentType
+	}
+
+	entry.NoCache = hasDirective(header, "no-cache")
+	entry.Expires = time.Time{}
+	if maxAge, ok := directiveValue(header, "max-age"); ok {
+		if seconds, err := strconv.Atoi(maxAge); err == nil && seconds > 0 {
+			entry.Expires = l.now().Add(time.Duration(seconds) * time.Second)
+		}
+	} else if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		entry.Expires = expires
+	}
+}
+
+// paths returns the files holding the document and metadata of rawURL.
+func (l *CachingLoader) paths(rawURL string) (data, meta string) {
+	sum := sha256.Sum256([]byte(rawURL))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(l.dir, key+".json"), filepath.Join(l.dir, key+".meta.json")
+}
+
+// read loads the cached document of rawURL. It reports false when there is
+// none, or when the stored files are unreadable.
+func (l *CachingLoader) read(rawURL string) (cacheEntry, []byte, bool) {
+	dataPath, metaPath := l.paths(rawURL)
+	var entry cacheEntry
+	meta, err := os.ReadFile(metaPath)
+	if err != nil || unmarshalJSON(meta, &entry) != nil || entry.URL != rawURL {
+		return cacheEntry{}, nil, false
+	}
+	data, err := os.ReadFile(dataPath)
+	if err != nil {
+		return cacheEntry{}, nil, false
+	}
+	return entry, data, true
+}
+
+// writeEntry stores entry and, unless data is nil, the document. Files are
+// replaced atomically so concurrent processes never read partial documents.
+func (l *CachingLoader) writeEntry(entry cacheEntry, data []byte) error {
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return fmt.Errorf("%w: %w", ErrDirectoryCreation, err)
+	}
+	dataPath, metaPath := l.paths(entry.URL)
+	if data != nil {
+		if err := writeFileAtomic(dataPath, data); err != nil {
+			return err
+		}
+	}
+	meta, err := marshalJSON(entry)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDataEncode, err)
+	}
+	return writeFileAtomic(metaPath, meta)
+}
+
+// writeFileAtomic writes data to a temporary file next to name and renames
+// it into place.
+func writeFileAtomic(name string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFileCreation, err)
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), name)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("%w: %w", ErrFileWrite, err)
+	}
+	return nil
+}
+
+// hasDirective reports whether the Cache-Control header contains directive.
+func hasDirective(header http.Header, directive string) bool {
+	_, ok := directiveValue(header, directive)
+	return ok
+}
+
+// directiveValue returns the value of a Cache-Control directive.
+func directiveValue(header http.Header, directive string) (string, bool) {
+	for _, value := range header.Values("Cache-Control") {
+		for part := range strings.SplitSeq(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if strings.EqualFold(name, directive) {
+				return strings.Trim(arg, `"`), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,171 @@
+package jsonschema
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheTestServer serves a string schema with the given Cache-Control header
+// and honors If-None-Match. It counts the requests carrying a body.
+type cacheTestServer struct {
+	*httptest.Server
+	cacheControl string
+	etag         atomic.Value
+	bodies       atomic.Int64
+}
+
+func newCacheTestServer(t *testing.T, cacheControl string) *cacheTestServer {
+	t.Helper()
+	s := &cacheTestServer{cacheControl: cacheControl}
+	s.etag.Store(`"v1"`)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := s.etag.Load().(string)
+		w.Header().Set("ETag", etag)
+		if s.cacheControl != "" {
+			w.Header().Set("Cache-Control", s.cacheControl)
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		s.bodies.Add(1)
+		_, _ = io.WriteString(w, `{"type": "string", "$comment": `+etag+`}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func loadString(t *testing.T, cache *CachingLoader, rawURL string) string {
+	t.Helper()
+	body, err := cache.Load(rawURL)
+	require.NoError(t, err)
+	defer func() { _ = body.Close() }()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCachingLoaderServesFreshEntries(t *testing.T) {
+	server := newCacheTestServer(t, "max-age=3600")
+	dir := t.TempDir()
+
+	cache := NewCachingLoader(CachingLoaderOptions{Dir: dir})
+	first := loadString(t, cache, server.URL)
+	assert.Equal(t, first, loadString(t, cache, server.URL))
+	assert.Equal(t, CachingLoaderStats{Hits: 1, Misses: 1}, cache.Stats())
+
+	// A new loader on the same directory, as in another process.
+	other := NewCachingLoader(CachingLoaderOptions{Dir: dir})
+	assert.Equal(t, first, loadString(t, other, server.URL))
+	assert.Equal(t, CachingLoaderStats{Hits: 1}, other.Stats())
+	assert.Equal(t, int64(1), server.bodies.Load())
+}
+
+func TestCachingLoaderRevalidatesStaleEntries(t *testing.T) {
+	server := newCacheTestServer(t, "no-cache")
+	cache := NewCachingLoader(CachingLoaderOptions{Dir: t.TempDir()})
+
+	v1 := loadString(t, cache, server.URL)
+	assert.Equal(t, v1, loadString(t, cache, server.URL))
+	assert.Equal(t, CachingLoaderStats{Revalidations: 1, Misses: 1}, cache.Stats())
+
+	server.etag.Store(`"v2"`)
+	assert.Contains(t, loadString(t, cache, server.URL), `"v2"`)
+	assert.Equal(t, CachingLoaderStats{Revalidations: 1, Misses: 2}, cache.Stats())
+}
+
+func TestCachingLoaderExpiry(t *testing.T) {
+	server := newCacheTestServer(t, "max-age=60")
+	cache := NewCachingLoader(CachingLoaderOptions{Dir: t.TempDir()})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	loadString(t, cache, server.URL)
+	now = now.Add(30 * time.Second)
+	loadString(t, cache, server.URL)
+	now = now.Add(time.Minute)
+	loadString(t, cache, server.URL)
+
+	assert.Equal(t, CachingLoaderStats{Hits: 1, Revalidations: 1, Misses: 1}, cache.Stats())
+}
+
+func TestCachingLoaderNoStore(t *testing.T) {
+	server := newCacheTestServer(t, "no-store")
+	cache := NewCachingLoader(CachingLoaderOptions{Dir: t.TempDir()})
+
+	loadString(t, cache, server.URL)
+	loadString(t, cache, server.URL)
+	assert.Equal(t, CachingLoaderStats{Misses: 2}, cache.Stats())
+}
+
+func TestCachingLoaderOffline(t *testing.T) {
+	server := newCacheTestServer(t, "no-cache")
+	dir := t.TempDir()
+	online := NewCachingLoader(CachingLoaderOptions{Dir: dir})
+	cached := loadString(t, online, server.URL+"/cached.json")
+
+	offline := NewCachingLoader(CachingLoaderOptions{Dir: dir, Offline: true})
+	assert.Equal(t, cached, loadString(t, offline, server.URL+"/cached.json"))
+
+	_, err := offline.Load(server.URL + "/missing.json")
+	require.ErrorIs(t, err, ErrNetworkDisabled)
+	assert.Equal(t, CachingLoaderStats{Hits: 1}, offline.Stats())
+	assert.Equal(t, int64(1), server.bodies.Load())
+}
+
+func TestCachingLoaderWithCompiler(t *testing.T) {
+	server := newCacheTestServer(t, "max-age=3600")
+	cache := NewCachingLoader(CachingLoaderOptions{Dir: t.TempDir()})
+
+	for range 2 {
+		compiler := NewCompiler().RegisterLoader("http", cache.Load)
+		schema, err := compiler.Compile([]byte(`{"$ref": "` + server.URL + `/name.json"}`))
+		require.NoError(t, err)
+		assert.False(t, schema.Validate(1).IsValid())
+	}
+	assert.Equal(t, CachingLoaderStats{Hits: 1, Misses: 1}, cache.Stats())
+}
+
+func TestCachingLoaderServesUnstoredDocuments(t *testing.T) {
+	server := newCacheTestServer(t, "max-age=3600")
+	dir := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(dir, nil, 0o600))
+
+	cache := NewCachingLoader(CachingLoaderOptions{Dir: dir})
+	assert.Contains(t, loadString(t, cache, server.URL), `"v1"`, "a cache that cannot be written still serves the document")
+	assert.Contains(t, loadString(t, cache, server.URL), `"v1"`)
+	assert.Equal(t, CachingLoaderStats{Misses: 2}, cache.Stats())
+}
+
+func TestCachingLoaderServesEntriesDuringFetches(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	t.Cleanup(slow.Close)
+	server := newCacheTestServer(t, "max-age=3600")
+	cache := NewCachingLoader(CachingLoaderOptions{Dir: t.TempDir()})
+	loadString(t, cache, server.URL)
+
+	fetched := make(chan struct{})
+	go func() {
+		defer close(fetched)
+		body, err := cache.Load(slow.URL)
+		if assert.NoError(t, err) {
+			_ = body.Close()
+		}
+	}()
+	assert.Contains(t, loadString(t, cache, server.URL), `"v1"`, "cached entries are served while another document is fetched")
+	close(release)
+	<-fetched
+}
@@ -0,0 +1,136 @@
+// Package main implements the jsonschema command line tool.
+//
+// Usage:
+//
+//	jsonschema <command> [flags] [files...]
+//
+// Commands:
+//
+//	migrate    Rewrite older schema drafts for a newer dialect
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command named by args[0] and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		showHelp(stderr)
+		return 2
+	}
+	switch args[0] {
+	case "migrate":
+		return runMigrate(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		showHelp(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "jsonschema: unknown command %q\n\n", args[0])
+		showHelp(stderr)
+		return 2
+	}
+}
+
+// dialects maps the names accepted by -to to dialects.
+var dialects = map[string]jsonschema.Dialect{
+	"2020-12":  jsonschema.Draft202012,
+	"2019-09":  jsonschema.Draft201909,
+	"draft-07": jsonschema.Draft7,
+	"draft-06": jsonschema.Draft6,
+}
+
+// runMigrate rewrites each file for the target dialect, printing the result
+// or writing it back with -w. Issues are reported on stderr.
+func runMigrate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	to := flags.String("to", "2020-12", "Target dialect: 2020-12, 2019-09, draft-07 or draft-06")
+	write := flags.Bool("w", false, "Write the result back to each file instead of printing it")
+	strict := flags.Bool("strict", false, "Fail when a construct cannot be migrated exactly")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: jsonschema migrate [-to dialect] [-w] [-strict] files...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	target, ok := dialects[*to]
+	if !ok {
+		fmt.Fprintf(stderr, "jsonschema: unknown dialect %q\n", *to)
+		return 2
+	}
+	files := flags.Args()
+	if len(files) == 0 {
+		flags.Usage()
+		return 2
+	}
+	if len(files) > 1 && !*write {
+		fmt.Fprintln(stderr, "jsonschema: migrating several files requires -w")
+		return 2
+	}
+
+	status := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "jsonschema: %v\n", err)
+			status = 1
+			continue
+		}
+		migrated, issues, err := jsonschema.Migrate(data, target)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", file, err)
+			status = 1
+			continue
+		}
+		for _, issue := range issues {
+			fmt.Fprintf(stderr, "%s%s\n", file, issue)
+		}
+		if len(issues) > 0 && *strict {
+			status = 1
+			continue
+		}
+		if !*write {
+			fmt.Fprintln(stdout, strings.TrimSuffix(string(migrated), "\n"))
+			continue
+		}
+		if strings.HasSuffix(string(data), "\n") && !strings.HasSuffix(string(migrated), "\n") {
+			migrated = append(migrated, '\n')
+		}
+		if err := os.WriteFile(file, migrated, 0o644); err != nil {
+			fmt.Fprintf(stderr, "jsonschema: %v\n", err)
+			status = 1
+		}
+	}
+	return status
+}
+
+// showHelp displays the help message.
+func showHelp(w io.Writer) {
+	fmt.Fprintln(w, `jsonschema - JSON Schema tool
+
+USAGE:
+    jsonschema <command> [flags] [files...]
+
+COMMANDS:
+    migrate    Rewrite Draft-04, Draft-06, Draft-07 and Draft 2019-09 schemas
+               for a newer dialect, keeping key order
+
+EXAMPLES:
+    # Print a Draft-07 schema migrated to Draft 2020-12
+    jsonschema migrate schema.json
+
+    # Migrate schemas in place to Draft 2019-09
+    jsonschema migrate -to 2019-09 -w schemas/*.json`)
+}
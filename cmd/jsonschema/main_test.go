@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateCommand(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "schema.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"$schema": "http://json-schema.org/draft-07/schema#", "$ref": "#/definitions/a", "type": "string", "definitions": {"a": {}}}`+"\n"), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"migrate", file}, &stdout, &stderr))
+	assert.Equal(t, `{"$schema":"https://json-schema.org/draft/2020-12/schema","$ref":"#/$defs/a","$defs":{"a":{}}}`+"\n", stdout.String())
+	assert.Equal(t, file+"#: keywords next to $ref were ignored and are removed: type\n", stderr.String())
+
+	stdout.Reset()
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"migrate", "-strict", "-w", file}, &stdout, &stderr), "strict mode fails on issues")
+	assert.Empty(t, stdout.String())
+
+	assert.Equal(t, 0, run([]string{"migrate", "-to", "2019-09", "-w", file}, &stdout, &stderr))
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, `{"$schema":"https://json-schema.org/draft/2019-09/schema","$ref":"#/$defs/a","$defs":{"a":{}}}`+"\n", string(data))
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run(nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"frobnicate"}, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"migrate", "-to", "draft-04", "a.json"}, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"migrate", "a.json", "b.json"}, &stdout, &stderr))
+	assert.Equal(t, 1, run([]string{"migrate", filepath.Join(t.TempDir(), "missing.json")}, &stdout, &stderr))
+	assert.Equal(t, 0, run([]string{"help"}, &stdout, &stderr))
+}
@@ -1,17 +1,18 @@
 package jsonschema
 
 import (
-	"context"
+	"bytes"
+	"container/list"
 	"encoding/base64"
 	stdjson "encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"maps"
 	"slices"
 	"sync"
-	"time"
+	"weak"
 
 	"github.com/go-json-experiment/json"
 	"github.com/go-json-experiment/json/jsontext"
@@ -86,7 +87,8 @@ type FormatDef struct {
 
 // Compiler represents a JSON Schema compiler that manages schema compilation and caching.
 type Compiler struct {
-	mu             sync.RWMutex                                       // Protects concurrent access to schemas map
+	mu             sync.RWMutex                                       // Protects concurrent access to schemas map and registries
+	parent         *Compiler                                          // Compiler this one was derived from, see Derive.
 	schemas        map[string]*Schema                                 // Cache of compiled schemas.
 	unresolvedRefs map[string][]*Schema                               // Track schemas that have unresolved references by URI
 	Decoders       map[string]func(string) ([]byte, error)            // Decoders for various encoding formats.
@@ -98,6 +100,19 @@ type Compiler struct {
 	// If false (default), unknown keywords are stripped during compilation.
 	PreserveExtra  bool
 	defaultDialect Dialect
+	strictMode     StrictMode
+	fsMounts       []fsMount              // URI prefixes served from file systems, see RegisterFS.
+	anonymous      []weak.Pointer[Schema] // Documents compiled without a cacheable URI, see DependencyGraph.
+
+	// Schema cache bound, see SetSchemaCacheLimit.
+	cacheLimit   int                      // Maximum number of cached schemas, 0 for no limit.
+	recency      *list.List               // Cached URIs, most recently used first.
+	recencyIndex map[string]*list.Element // Elements of recency by URI.
+
+	// Lockfile state, see SetLockfile.
+	fetched    map[string]LockEntry // Pins of the documents fetched through loaders.
+	lockfile   *Lockfile            // Pins to enforce, if any.
+	lockErrors map[string]error     // Lockfile violations by document URI.
 
 	// JSON encoder/decoder configuration
 	jsonEncoder func(v any) ([]byte, error)
@@ -152,10 +167,43 @@ func (c *Compiler) WithDecoderJSON(decoder func(data []byte, v any) error) *Comp
 
 // Compile compiles a JSON schema and caches it. If an URI is provided, it uses that as the key; otherwise, it generates a hash.
 func (c *Compiler) Compile(jsonSchema []byte, uris ...string) (*Schema, error) {
-	schema, err := newSchema(jsonSchema, c)
+	schema, compiled, err := c.compile(jsonSchema, uris...)
 	if err != nil {
 		return nil, err
 	}
+	if !compiled {
+		// The $id is cached: check the schema returned rather than
+		// jsonSchema, and leave the cache as it is if it fails.
+		jsonSchema = schema.source
+	}
+	if err := c.lockViolations(schema); err != nil {
+		if compiled {
+			c.discard(schema)
+		}
+		return nil, err
+	}
+	if jsonSchema == nil {
+		return schema, nil
+	}
+	if err := c.checkStrict(jsonSchema, schema); err != nil {
+		if compiled {
+			c.discard(schema)
+		}
+		return nil, err
+	}
+	return schema, nil
+}
+
+// compile compiles a schema without checking the lockfile, which is done
+// once for the schema passed to Compile rather than for every document
+// fetched while resolving its references. It reports false when the
+// schema's URI was already cached and the cached schema is returned.
+func (c *Compiler) compile(jsonSchema []byte, uris ...string) (*Schema, bool, error) {
+	schema, err := newSchema(jsonSchema, c)
+	if err != nil {
+		return nil, false, err
+	}
+	schema.source = bytes.Clone(jsonSchema)
 
 	if schema.ID == "" && len(uris) > 0 {
 		schema.ID = uris[0]
@@ -166,24 +214,29 @@ func (c *Compiler) Compile(jsonSchema []byte, uris ...string) (*Schema, error) {
 	if uri != "" && isValidURI(uri) {
 		schema.uri = uri
 
-		c.mu.RLock()
+		c.mu.Lock()
 		existingSchema, exists := c.schemas[uri]
-		c.mu.RUnlock()
+		if exists {
+			c.touchLocked(uri)
+		}
+		c.mu.Unlock()
 
 		if exists {
-			return existingSchema, nil
+			return existingSchema, false, nil
 		}
 	}
 
 	schema.initializeSchema(c, nil)
 
 	if err := schema.validateRegexSyntax(); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	c.mu.Lock()
 	if schema.uri != "" && isValidURI(schema.uri) {
-		c.schemas[schema.uri] = schema
+		c.storeLocked(schema.uri, schema)
+	} else {
+		c.trackAnonymousLocked(schema)
 	}
 
 	// Track unresolved references from this schema
@@ -208,7 +261,8 @@ func (c *Compiler) Compile(jsonSchema []byte, uris ...string) (*Schema, error) {
 		c.mu.Unlock()
 	}
 
-	return schema, nil
+	c.evict()
+	return schema, true, nil
 }
 
 // trackUnresolvedReferences tracks which schemas have unresolved references to which URIs.
@@ -225,20 +279,18 @@ func (c *Compiler) trackUnresolvedReferences(schema *Schema) {
 func (c *Compiler) resolveSchemaURL(url string) (*Schema, error) {
 	id, anchor := splitRef(url)
 
-	c.mu.RLock()
-	schema, exists := c.schemas[id]
-	c.mu.RUnlock()
-
-	if exists {
+	if schema, exists := c.cachedSchema(id); exists {
 		return schema, nil
 	}
 
-	loader, ok := c.Loaders[getURLScheme(url)]
-	if !ok {
-		return nil, ErrNoLoaderRegistered
+	body, mounted, err := c.openMounted(id)
+	if !mounted {
+		loader, ok := c.loader(getURLScheme(url))
+		if !ok {
+			return nil, ErrNoLoaderRegistered
+		}
+		body, err = loader(url)
 	}
-
-	body, err := loader(url)
 	if err != nil {
 		return nil, fmt.Errorf("loading schema from %s: %w", url, err)
 	}
@@ -249,7 +301,19 @@ func (c *Compiler) resolveSchemaURL(url string) (*Schema, error) {
 		return nil, fmt.Errorf("reading from %s: %w", url, err)
 	}
 
-	compiledSchema, err := c.Compile(data, id)
+	if !mounted {
+		if err := c.pin(id, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if isYAMLDocument(id, body) {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("reading from %s: %w", url, err)
+		}
+	}
+
+	compiledSchema, _, err := c.compile(data, id)
 	if err != nil {
 		return nil, err
 	}
@@ -264,7 +328,8 @@ func (c *Compiler) resolveSchemaURL(url string) (*Schema, error) {
 // SetSchema associates a specific schema with a URI.
 func (c *Compiler) SetSchema(uri string, schema *Schema) *Compiler {
 	c.mu.Lock()
-	c.schemas[uri] = schema
+	c.storeLocked(uri, schema)
+	c.evictLocked()
 	c.mu.Unlock()
 	return c
 }
@@ -273,11 +338,7 @@ func (c *Compiler) SetSchema(uri string, schema *Schema) *Compiler {
 func (c *Compiler) Schema(ref string) (*Schema, error) {
 	baseURI, anchor := splitRef(ref)
 
-	c.mu.RLock()
-	schema, exists := c.schemas[baseURI]
-	c.mu.RUnlock()
-
-	if exists {
+	if schema, exists := c.cachedSchema(baseURI); exists {
 		if baseURI == ref {
 			return schema, nil
 		}
@@ -317,24 +378,30 @@ func (c *Compiler) SetPreserveExtra(preserve bool) *Compiler {
 
 // RegisterDecoder adds a new decoder function for a specific encoding.
 func (c *Compiler) RegisterDecoder(encodingName string, decoderFunc func(string) ([]byte, error)) *Compiler {
+	c.mu.Lock()
 	c.Decoders[encodingName] = decoderFunc
+	c.mu.Unlock()
 	return c
 }
 
 // RegisterMediaType adds a new unmarshal function for a specific media type.
 func (c *Compiler) RegisterMediaType(mediaTypeName string, unmarshalFunc func([]byte) (any, error)) *Compiler {
+	c.mu.Lock()
 	c.MediaTypes[mediaTypeName] = unmarshalFunc
+	c.mu.Unlock()
 	return c
 }
 
 // RegisterLoader adds a new loader function for a specific URI scheme.
 //
 // "http" and "https" are pre-registered with a 10s timeout client. When
-// schemas come from untrusted sources, replace those loaders or remove them
-// (delete from c.Loaders) so external $ref resolution is gated by your own
-// host, size, and timeout policy.
+// schemas come from untrusted sources, restrict them with
+// SetHTTPLoaderOptions, or replace or remove them (delete from c.Loaders).
+// "file" URLs are not loaded unless a FileLoader is registered.
 func (c *Compiler) RegisterLoader(scheme string, loaderFunc func(url string) (io.ReadCloser, error)) *Compiler {
+	c.mu.Lock()
 	c.Loaders[scheme] = loaderFunc
+	c.mu.Unlock()
 	return c
 }
 
@@ -350,15 +417,6 @@ func (c *Compiler) RegisterDefaultFunc(name string, fn DefaultFunc) *Compiler {
 	return c
 }
 
-// defaultFunc retrieves a registered default function by name.
-func (c *Compiler) defaultFunc(name string) (DefaultFunc, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	fn, exists := c.defaultFuncs[name]
-	return fn, exists
-}
-
 // initDefaults initializes default values for decoders, media types, and loaders.
 func (c *Compiler) initDefaults() {
 	c.Decoders["base64"] = base64.StdEncoding.DecodeString
@@ -395,35 +453,7 @@ func (c *Compiler) setupMediaTypes() {
 
 // setupLoaders configures default loaders for fetching schemas via HTTP/HTTPS.
 func (c *Compiler) setupLoaders() {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	defaultHTTPLoader := func(url string) (io.ReadCloser, error) {
-		req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %w", ErrNetworkFetch, err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			_ = resp.Body.Close()
-			return nil, fmt.Errorf("%w: %w", ErrInvalidStatusCode, &HTTPStatusError{
-				URL:        url,
-				StatusCode: resp.StatusCode,
-				Status:     resp.Status,
-			})
-		}
-
-		return resp.Body, nil
-	}
-
-	c.RegisterLoader("http", defaultHTTPLoader)
-	c.RegisterLoader("https", defaultHTTPLoader)
+	c.SetHTTPLoaderOptions(HTTPLoaderOptions{})
 }
 
 // CompileBatch compiles multiple schemas efficiently by deferring reference resolution
@@ -438,6 +468,7 @@ func (c *Compiler) CompileBatch(schemas map[string][]byte) (map[string]*Schema,
 		if err != nil {
 			return nil, fmt.Errorf("compiling schema %s: %w", id, err)
 		}
+		schema.source = bytes.Clone(schemaBytes)
 
 		if schema.ID == "" {
 			schema.ID = id
@@ -453,7 +484,9 @@ func (c *Compiler) CompileBatch(schemas map[string][]byte) (map[string]*Schema,
 
 		c.mu.Lock()
 		if schema.uri != "" && isValidURI(schema.uri) {
-			c.schemas[schema.uri] = schema
+			c.storeLocked(schema.uri, schema)
+		} else {
+			c.trackAnonymousLocked(schema)
 		}
 		c.mu.Unlock()
 	}
@@ -462,6 +495,23 @@ func (c *Compiler) CompileBatch(schemas map[string][]byte) (map[string]*Schema,
 	for _, schema := range compiledSchemas {
 		schema.resolveReferences()
 	}
+	c.evict()
+
+	var errs []error
+	for _, id := range slices.Sorted(maps.Keys(compiledSchemas)) {
+		if err := c.lockViolations(compiledSchemas[id]); err != nil {
+			errs = append(errs, fmt.Errorf("compiling schema %s: %w", id, err))
+		}
+		if err := c.checkStrict(schemas[id], compiledSchemas[id]); err != nil {
+			errs = append(errs, fmt.Errorf("compiling schema %s: %w", id, err))
+		}
+	}
+	if len(errs) > 0 {
+		for _, schema := range compiledSchemas {
+			c.discard(schema)
+		}
+		return nil, errors.Join(errs...)
+	}
 
 	return compiledSchemas, nil
 }
@@ -490,6 +540,10 @@ func (c *Compiler) UnregisterFormat(name string) *Compiler {
 	c.customFormatsRW.Lock()
 	defer c.customFormatsRW.Unlock()
 
+	if c.parent != nil {
+		c.customFormats[name] = nil // Hides the format of the parent.
+		return c
+	}
 	delete(c.customFormats, name)
 	return c
 }
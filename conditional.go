@@ -44,8 +44,7 @@ func evaluateConditional(
 				results = append(results, thenResult)
 
 				if !thenResult.IsValid() {
-					return results, NewEvaluationError("then", "if_then_mismatch",
-						"Value meets the 'if' condition but does not match the 'then' schema")
+					return results, newError("then", CodeIfThenMismatch)
 				}
 				mergeStringMaps(evaluatedProps, thenEvaluatedProps)
 				mergeIntMaps(evaluatedItems, thenEvaluatedItems)
@@ -54,11 +53,13 @@ func evaluateConditional(
 	} else if schema.Else != nil {
 		elseResult, elseEvaluatedProps, elseEvaluatedItems := schema.Else.evaluate(instance, dynamicScope)
 		if elseResult != nil {
+			elseResult.SetEvaluationPath("/else").
+				SetSchemaLocation(schema.SchemaLocation("/else"))
+
 			results = append(results, elseResult)
 
 			if !elseResult.IsValid() {
-				return results, NewEvaluationError("else", "if_else_mismatch",
-					"Value fails the 'if' condition and does not match the 'else' schema")
+				return results, newError("else", CodeIfElseMismatch)
 			}
 			mergeStringMaps(evaluatedProps, elseEvaluatedProps)
 			mergeIntMaps(evaluatedItems, elseEvaluatedItems)
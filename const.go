@@ -19,7 +19,7 @@ func evaluateConst(schema *Schema, instance any) *EvaluationError {
 	}
 
 	if schema.Const.Value == nil {
-		return NewEvaluationError("const", "const_mismatch_null", "Value should be null")
+		return newError("const", CodeConstMismatchNull)
 	}
-	return NewEvaluationError("const", "const_mismatch", "Value does not match the constant value")
+	return newError("const", CodeConstMismatch)
 }
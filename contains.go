@@ -50,19 +50,17 @@ func evaluateContains(
 
 	// Check minContains validation (skip if minContains is 0 and no valid items found - valid scenario)
 	if (minContains != 0 || validCount != 0) && validCount < minContains {
-		return results, NewEvaluationError(
-			"minContains", "contains_too_few_items",
-			"Value should contain at least {min_contains} matching items",
+		return results, newError(
+			"minContains", CodeContainsTooFewItems,
 			map[string]any{"min_contains": minContains, "count": validCount},
 		)
 	}
 
 	// Handle 'maxContains' logic
 	if schema.MaxContains != nil && validCount > int(*schema.MaxContains) {
-		return results, NewEvaluationError(
-			"maxContains", "contains_too_many_items",
-			"Value should contain no more than {max_contains} matching items",
-			map[string]any{"max_contains": *schema.MaxContains, "count": validCount},
+		return results, newError(
+			"maxContains", CodeContainsTooManyItems,
+			map[string]any{"max_contains": int(*schema.MaxContains), "count": validCount},
 		)
 	}
 
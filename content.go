@@ -25,15 +25,15 @@ func evaluateContent(schema *Schema, instance any, _ map[string]bool, _ map[int]
 
 	// Decode the content if encoding is specified
 	if schema.ContentEncoding != nil {
-		decoder, exists := schema.compiler.Decoders[*schema.ContentEncoding]
+		decoder, exists := schema.compiler.decoder(*schema.ContentEncoding)
 		if !exists {
-			return nil, NewEvaluationError("contentEncoding", "unsupported_encoding", "Encoding '{encoding}' is not supported", map[string]any{
+			return nil, newError("contentEncoding", CodeUnsupportedEncoding, map[string]any{
 				"encoding": *schema.ContentEncoding,
 			})
 		}
 		content, err = decoder(value)
 		if err != nil {
-			return nil, NewEvaluationError("contentEncoding", "invalid_encoding", "Error decoding data with '{encoding}'", map[string]any{
+			return nil, newError("contentEncoding", CodeInvalidEncoding, map[string]any{
 				"encoding": *schema.ContentEncoding,
 				"error":    err.Error(),
 			})
@@ -44,15 +44,15 @@ func evaluateContent(schema *Schema, instance any, _ map[string]bool, _ map[int]
 
 	// Handle content media type validation
 	if schema.ContentMediaType != nil {
-		unmarshal, exists := schema.compiler.MediaTypes[*schema.ContentMediaType]
+		unmarshal, exists := schema.compiler.mediaType(*schema.ContentMediaType)
 		if !exists {
-			return nil, NewEvaluationError("contentMediaType", "unsupported_media_type", "Media type '{media_type}' is not supported", map[string]any{
+			return nil, newError("contentMediaType", CodeUnsupportedMediaType, map[string]any{
 				"media_type": *schema.ContentMediaType,
 			})
 		}
 		parsedValue, err = unmarshal(content)
 		if err != nil {
-			return nil, NewEvaluationError("contentMediaType", "invalid_media_type", "Error unmarshalling data with media type '{media_type}'", map[string]any{
+			return nil, newError("contentMediaType", CodeInvalidMediaType, map[string]any{
 				"media_type": *schema.ContentMediaType,
 				"error":      err.Error(),
 			})
@@ -69,7 +69,7 @@ func evaluateContent(schema *Schema, instance any, _ map[string]bool, _ map[int]
 				SetSchemaLocation(schema.SchemaLocation("/contentSchema"))
 
 			if !result.IsValid() {
-				return result, NewEvaluationError("contentSchema", "content_schema_mismatch", "Content does not match the schema")
+				return result, newError("contentSchema", CodeContentSchemaMismatch)
 			}
 			return result, nil
 		}
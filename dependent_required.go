@@ -38,10 +38,9 @@ func evaluateDependentRequired(schema *Schema, object map[string]any) *Evaluatio
 
 	if len(dependentMissingProps) > 0 {
 		// json.Marshal on map[string][]string never fails in practice.
-		missingPropsJSON, _ := json.Marshal(dependentMissingProps)
-		return NewEvaluationError(
-			"dependentRequired", "dependent_property_required",
-			"Some required property dependencies are missing: {missing_properties}",
+		missingPropsJSON, _ := json.Marshal(dependentMissingProps, json.Deterministic(true))
+		return newError(
+			"dependentRequired", CodeDependentPropertyRequired,
 			map[string]any{"missing_properties": string(missingPropsJSON)},
 		)
 	}
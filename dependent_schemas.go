@@ -2,6 +2,7 @@ package jsonschema
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -53,20 +54,19 @@ func evaluateDependentSchemas(
 	}
 
 	if len(invalidProperties) == 1 {
-		return results, NewEvaluationError(
-			"dependentSchemas", "dependent_schema_mismatch",
-			"Property {property} does not match the dependent schema",
+		return results, newError(
+			"dependentSchemas", CodeDependentSchemaMismatch,
 			map[string]any{"property": fmt.Sprintf("'%s'", invalidProperties[0])},
 		)
 	}
 	if len(invalidProperties) > 1 {
+		slices.Sort(invalidProperties)
 		quotedProperties := make([]string, len(invalidProperties))
 		for i, prop := range invalidProperties {
 			quotedProperties[i] = fmt.Sprintf("'%s'", prop)
 		}
-		return results, NewEvaluationError(
-			"dependentSchemas", "dependent_schemas_mismatch",
-			"Properties {properties} do not match the dependent schemas",
+		return results, newError(
+			"dependentSchemas", CodeDependentSchemasMismatch,
 			map[string]any{"properties": strings.Join(quotedProperties, ", ")},
 		)
 	}
@@ -0,0 +1,400 @@
+package jsonschema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// DereferenceOptions configures Schema.Dereference.
+type DereferenceOptions struct {
+	// MaxDepth is how many times a recursive reference is expanded along a
+	// single path before it is left as a "$ref". Zero makes recursive
+	// references fail with ErrCircularReference.
+	MaxDepth int
+}
+
+// Dereference returns s with every "$ref" and "$dynamicRef" replaced by a
+// copy of the schema it resolves to, for tools that cannot follow
+// references. A reference without sibling keywords is replaced outright; one
+// with siblings becomes an "allOf" entry next to them, which keeps the
+// validation behavior. Inlined copies lose "$id", "$anchor" and "$defs", so
+// they do not change the base URI of their new location. "$dynamicRef" is
+// replaced by its static target, as if no dynamic scope applied.
+//
+// A reference that recurses more than opts.MaxDepth times is left in place,
+// pointing to where its target is written in the output; the root keeps the
+// definitions such references point into. With MaxDepth zero, recursion is
+// an error naming the cycle.
+//
+// The output of a Draft 2020-12 or Draft-07 schema is written in the
+// keywords of its dialect; a Draft-07 output fails with ErrLossyDowngrade
+// when inlined schemas use keywords Draft-07 cannot express. Other dialects
+// are written in Draft 2020-12 keywords and declare Draft 2020-12 as
+// "$schema".
+func (s *Schema) Dereference(opts DereferenceOptions) ([]byte, error) {
+	d := &dereferencer{opts: opts, pointers: schemaPointers(s)}
+	if s.ID != "" {
+		d.base, _ = splitRef(s.uri)
+	}
+	value, err := d.schema(s, true)
+	if err != nil {
+		return nil, err
+	}
+	if s.Boolean != nil {
+		return value, nil
+	}
+
+	var object map[string]jsontext.Value
+	if err := json.Unmarshal(value, &object); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+	}
+	// Definitions are only useful as targets of the references left in place.
+	defs, err := d.definitions(s)
+	if err != nil {
+		return nil, err
+	}
+	delete(object, "$defs")
+	if len(defs) > 0 {
+		if object["$defs"], err = json.Marshal(defs, json.Deterministic(true)); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+		}
+	}
+
+	switch s.Dialect() {
+	case Draft202012:
+	case Draft7:
+		data, err := json.Marshal(object, json.Deterministic(true))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+		}
+		data, _, err = downgradeDocument(data, DowngradeOptions{})
+		return data, err
+	default:
+		object["$schema"], _ = json.Marshal(string(Draft202012))
+	}
+	return json.Marshal(object, json.Deterministic(true))
+}
+
+// dereferencer inlines references depth first, tracking the references
+// expanded on the current path to detect recursion.
+type dereferencer struct {
+	opts     DereferenceOptions
+	stack    []dereferenceFrame
+	pointers map[*Schema]string // Output locations of the schemas written in place.
+	base     string             // URI of the output, when its root has an "$id".
+	kept     []string           // Output locations the references left in place point to.
+}
+
+type dereferenceFrame struct {
+	target   *Schema
+	location string
+}
+
+// identityKeywords are dropped from inlined copies.
+var identityKeywords = []string{"$id", "$schema", "$anchor", "$dynamicAnchor", "$defs"}
+
+// schema returns the dereferenced form of s. The root keeps its identity
+// keywords and definitions.
+func (d *dereferencer) schema(s *Schema, root bool) (jsontext.Value, error) {
+	if s.Boolean != nil {
+		return json.Marshal(*s.Boolean)
+	}
+	object, err := schemaObject(s)
+	if err != nil {
+		return nil, err
+	}
+	if !root {
+		for _, keyword := range identityKeywords {
+			delete(object, keyword)
+		}
+	}
+
+	if err := d.subschemas(s, object); err != nil {
+		return nil, err
+	}
+
+	for _, ref := range []struct {
+		keyword  string
+		ref      string
+		resolved *Schema
+	}{{"$ref", s.Ref, s.ResolvedRef}, {"$dynamicRef", s.DynamicRef, s.ResolvedDynamicRef}} {
+		if ref.ref == "" {
+			continue
+		}
+		value, inlined, err := d.reference(s, ref.ref, ref.resolved)
+		if err != nil {
+			return nil, err
+		}
+		if !inlined {
+			object[ref.keyword] = value
+			continue
+		}
+		delete(object, ref.keyword)
+		if s.Dialect().refIgnoresSiblings() {
+			for keyword := range object {
+				if !slices.Contains(identityKeywords, keyword) {
+					delete(object, keyword)
+				}
+			}
+		}
+		if onlyIdentityKeywords(object) {
+			if !root || len(object) == 0 {
+				return value, nil
+			}
+			// Keep the identity of the root where the target allows it.
+			if merged, ok := mergeSchemaObjects(object, value); ok {
+				return merged, nil
+			}
+		}
+		var allOf []jsontext.Value
+		if raw, ok := object["allOf"]; ok {
+			if err := json.Unmarshal(raw, &allOf); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+			}
+		}
+		if object["allOf"], err = json.Marshal(append(allOf, value)); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+		}
+	}
+
+	return json.Marshal(object, json.Deterministic(true))
+}
+
+// reference returns the inlined target of ref, or, when the recursion limit
+// is reached, the absolute form of ref with inlined false.
+func (d *dereferencer) reference(s *Schema, ref string, target *Schema) (jsontext.Value, bool, error) {
+	if target == nil {
+		return nil, false, fmt.Errorf("%w: %s", ErrUnresolvedReference, ref)
+	}
+	location := referenceLocation(s, ref)
+
+	depth := 0
+	for _, frame := range d.stack {
+		if frame.target == target {
+			depth++
+		}
+	}
+	if depth > 0 && depth > d.opts.MaxDepth {
+		if d.opts.MaxDepth == 0 {
+			return nil, false, fmt.Errorf("%w: %s", ErrCircularReference, d.cyclePath(target, location))
+		}
+		if pointer, ok := d.pointers[target]; ok {
+			location = d.base + "#" + pointer
+			d.kept = append(d.kept, pointer)
+		}
+		value, err := json.Marshal(location)
+		return value, false, err
+	}
+
+	d.stack = append(d.stack, dereferenceFrame{target: target, location: location})
+	defer func() { d.stack = d.stack[:len(d.stack)-1] }()
+	value, err := d.schema(target, false)
+	return value, true, err
+}
+
+// definitions returns the dereferenced definitions of root that references
+// left in place point into. Each is expanded as if reached through a
+// reference, so references within it recurse as deep as elsewhere.
+func (d *dereferencer) definitions(root *Schema) (map[string]jsontext.Value, error) {
+	defs := make(map[string]jsontext.Value)
+	for pending := true; pending; {
+		pending = false
+		for _, name := range slices.Sorted(maps.Keys(root.Defs)) {
+			pointer := "/$defs/" + pointerEscaper.Replace(name)
+			if _, done := defs[name]; done || !d.keeps(pointer) {
+				continue
+			}
+			def := root.Defs[name]
+			d.stack = []dereferenceFrame{{target: def, location: d.base + "#" + pointer}}
+			value, err := d.schema(def, false)
+			d.stack = nil
+			if err != nil {
+				return nil, err
+			}
+			defs[name] = value
+			pending = true // The definition may keep references to others.
+		}
+	}
+	return defs, nil
+}
+
+// keeps reports whether a reference left in place points to pointer or
+// within it.
+func (d *dereferencer) keeps(pointer string) bool {
+	return slices.ContainsFunc(d.kept, func(kept string) bool {
+		return kept == pointer || strings.HasPrefix(kept, pointer+"/")
+	})
+}
+
+// cyclePath lists the references from the first expansion of target to the
+// reference closing the cycle.
+func (d *dereferencer) cyclePath(target *Schema, location string) string {
+	start := slices.IndexFunc(d.stack, func(frame dereferenceFrame) bool { return frame.target == target })
+	path := make([]string, 0, len(d.stack)-start+1)
+	for _, frame := range d.stack[start:] {
+		path = append(path, frame.location)
+	}
+	return strings.Join(append(path, location), " -> ")
+}
+
+// subschemas replaces the subschemas of s in object with their dereferenced
+// forms. Definitions are left as they are; they are dropped from inlined
+// copies and only kept on the root for references left in place.
+func (d *dereferencer) subschemas(s *Schema, object map[string]jsontext.Value) error {
+	for _, field := range []struct {
+		keyword string
+		child   *Schema
+	}{
+		{"not", s.Not},
+		{"if", s.If},
+		{"then", s.Then},
+		{"else", s.Else},
+		{"items", s.Items},
+		{"contains", s.Contains},
+		{"additionalProperties", s.AdditionalProperties},
+		{"propertyNames", s.PropertyNames},
+		{"unevaluatedItems", s.UnevaluatedItems},
+		{"unevaluatedProperties", s.UnevaluatedProperties},
+		{"contentSchema", s.ContentSchema},
+	} {
+		if field.child == nil {
+			continue
+		}
+		value, err := d.schema(field.child, false)
+		if err != nil {
+			return err
+		}
+		object[field.keyword] = value
+	}
+
+	for _, field := range []struct {
+		keyword  string
+		children []*Schema
+	}{
+		{"allOf", s.AllOf},
+		{"anyOf", s.AnyOf},
+		{"oneOf", s.OneOf},
+		{"prefixItems", s.PrefixItems},
+	} {
+		if len(field.children) == 0 {
+			continue
+		}
+		values := make([]jsontext.Value, len(field.children))
+		for i, child := range field.children {
+			value, err := d.schema(child, false)
+			if err != nil {
+				return err
+			}
+			values[i] = value
+		}
+		object[field.keyword], _ = json.Marshal(values)
+	}
+
+	for _, field := range []struct {
+		keyword  string
+		children map[string]*Schema
+	}{
+		{"properties", schemaMap(s.Properties)},
+		{"patternProperties", schemaMap(s.PatternProperties)},
+		{"dependentSchemas", s.DependentSchemas},
+	} {
+		if len(field.children) == 0 {
+			continue
+		}
+		values := make(map[string]jsontext.Value, len(field.children))
+		for _, name := range slices.Sorted(maps.Keys(field.children)) {
+			value, err := d.schema(field.children[name], false)
+			if err != nil {
+				return err
+			}
+			values[name] = value
+		}
+		object[field.keyword], _ = json.Marshal(values, json.Deterministic(true))
+	}
+	return nil
+}
+
+// schemaPointers maps s and the subschemas Dereference writes in place to
+// their JSON Pointers in the output, which uses Draft 2020-12 keywords. Only
+// the definitions of s are written out.
+func schemaPointers(s *Schema) map[*Schema]string {
+	pointers := make(map[*Schema]string)
+	var walk func(s *Schema, pointer string)
+	walk = func(s *Schema, pointer string) {
+		if _, seen := pointers[s]; seen {
+			return
+		}
+		pointers[s] = pointer
+		for _, child := range strictChildren(s, pointer) {
+			if !strings.HasPrefix(child.location, pointer+"/$defs/") && !strings.HasPrefix(child.location, pointer+"/definitions/") {
+				walk(child.schema, child.location)
+			}
+		}
+	}
+	walk(s, "")
+	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+		walk(s.Defs[name], "/$defs/"+pointerEscaper.Replace(name))
+	}
+	return pointers
+}
+
+// mergeSchemaObjects adds the keywords of the schema value to object. It
+// reports false when value is a boolean schema or shares a keyword with
+// object.
+func mergeSchemaObjects(object map[string]jsontext.Value, value jsontext.Value) (jsontext.Value, bool) {
+	var target map[string]jsontext.Value
+	if json.Unmarshal(value, &target) != nil {
+		return nil, false
+	}
+	for keyword, raw := range target {
+		if _, ok := object[keyword]; ok {
+			return nil, false
+		}
+		object[keyword] = raw
+	}
+	merged, err := json.Marshal(object, json.Deterministic(true))
+	return merged, err == nil
+}
+
+func schemaMap(m *SchemaMap) map[string]*Schema {
+	if m == nil {
+		return nil
+	}
+	return *m
+}
+
+// onlyIdentityKeywords reports whether object has no keywords besides
+// identity keywords and "$comment".
+func onlyIdentityKeywords(object map[string]jsontext.Value) bool {
+	for keyword := range object {
+		if keyword != "$comment" && !slices.Contains(identityKeywords, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// referenceLocation returns ref resolved against the resource of s, so it
+// resolves the same from any position. References within a resource without
+// URI are returned unchanged.
+func referenceLocation(s *Schema, ref string) string {
+	if isAbsoluteURI(ref) {
+		return ref
+	}
+	if strings.HasPrefix(ref, "#") {
+		if uri := s.scopeSchema().uri; uri != "" {
+			base, _ := splitRef(uri)
+			return base + ref
+		}
+		return ref
+	}
+	if s.baseURI != "" {
+		return resolveRelativeURI(s.baseURI, ref)
+	}
+	return ref
+}
@@ -0,0 +1,214 @@
+package jsonschema
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDereferenceInlinesReferences(t *testing.T) {
+	compiler := NewCompiler().RegisterFS("https://example.com/schemas/", fstest.MapFS{
+		"name.json":           {Data: []byte(`{"$id": "https://example.com/schemas/name.json", "type": "string", "minLength": 2}`)},
+		"common/address.json": {Data: []byte(`{"$defs": {"zip": {"type": "string"}}, "type": "object", "properties": {"zip": {"$ref": "#/$defs/zip"}, "country": {"$ref": "../name.json"}}}`)},
+	})
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/schemas/user.json",
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"name": {"$ref": "name.json"},
+			"nickname": {"$ref": "name.json", "maxLength": 10},
+			"address": {"$ref": "common/address.json"},
+			"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}}
+		},
+		"$defs": {"tag": {"type": "string", "enum": ["a", "b"]}}
+	}`))
+	require.NoError(t, err)
+
+	dereferenced, err := schema.Dereference(DereferenceOptions{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$id": "https://example.com/schemas/user.json",
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 2},
+			"nickname": {"maxLength": 10, "allOf": [{"type": "string", "minLength": 2}]},
+			"address": {
+				"type": "object",
+				"properties": {
+					"zip": {"type": "string"},
+					"country": {"type": "string", "minLength": 2}
+				}
+			},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}}
+		}
+	}`, string(dereferenced))
+}
+
+func TestDereferenceRootReference(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "#/$defs/user",
+		"$defs": {"user": {"type": "object", "required": ["id"]}}
+	}`))
+	require.NoError(t, err)
+
+	dereferenced, err := schema.Dereference(DereferenceOptions{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["id"]
+	}`, string(dereferenced))
+}
+
+func TestDereferenceCycle(t *testing.T) {
+	source := []byte(`{
+		"$id": "https://example.com/tree.json",
+		"$ref": "#/$defs/node",
+		"$defs": {
+			"node": {
+				"type": "object",
+				"properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}
+			}
+		}
+	}`)
+	schema, err := NewCompiler().Compile(source)
+	require.NoError(t, err)
+
+	_, err = schema.Dereference(DereferenceOptions{})
+	require.ErrorIs(t, err, ErrCircularReference)
+	assert.Contains(t, err.Error(), "https://example.com/tree.json#/$defs/node -> https://example.com/tree.json#/$defs/node")
+
+	dereferenced, err := schema.Dereference(DereferenceOptions{MaxDepth: 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$id": "https://example.com/tree.json",
+		"$defs": {
+			"node": {
+				"type": "object",
+				"properties": {"children": {"type": "array", "items": {
+					"type": "object",
+					"properties": {"children": {"type": "array", "items": {"$ref": "https://example.com/tree.json#/$defs/node"}}}
+				}}}
+			}
+		},
+		"type": "object",
+		"properties": {"children": {"type": "array", "items": {
+			"type": "object",
+			"properties": {"children": {"type": "array", "items": {"$ref": "https://example.com/tree.json#/$defs/node"}}}
+		}}}
+	}`, string(dereferenced))
+
+	// The output still validates like the original.
+	recompiled, err := NewCompiler().Compile(dereferenced)
+	require.NoError(t, err)
+	deep := map[string]any{"children": []any{map[string]any{"children": []any{map[string]any{"children": []any{1}}}}}}
+	assert.False(t, schema.Validate(deep).IsValid())
+	assert.False(t, recompiled.Validate(deep).IsValid())
+}
+
+func TestDereferenceDraft7(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "array",
+		"items": [{"$ref": "#/definitions/name"}],
+		"additionalItems": false,
+		"dependencies": {"a": ["b"], "c": {"$ref": "#/definitions/name"}},
+		"definitions": {"name": {"type": "string"}}
+	}`))
+	require.NoError(t, err)
+
+	dereferenced, err := schema.Dereference(DereferenceOptions{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "array",
+		"items": [{"type": "string"}],
+		"additionalItems": false,
+		"dependencies": {"a": ["b"], "c": {"type": "string"}}
+	}`, string(dereferenced))
+
+	recompiled, err := NewCompiler().Compile(dereferenced)
+	require.NoError(t, err)
+	for _, instance := range []any{[]any{"x"}, []any{"x", "y"}, []any{1}} {
+		assert.Equal(t, schema.Validate(instance).IsValid(), recompiled.Validate(instance).IsValid(), "%v", instance)
+	}
+}
+
+func TestDereferenceDraft7Lossy(t *testing.T) {
+	compiler := NewCompiler().RegisterFS("https://example.com/schemas/", fstest.MapFS{
+		"tags.json": {Data: []byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "array", "contains": {"type": "string"}, "maxContains": 2}`)},
+	})
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/schemas/item.json",
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"properties": {"tags": {"$ref": "tags.json"}}
+	}`))
+	require.NoError(t, err)
+
+	_, err = schema.Dereference(DereferenceOptions{})
+	require.ErrorIs(t, err, ErrLossyDowngrade)
+}
+
+func TestDereferenceDraft7Cycle(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$ref": "#/definitions/node",
+		"definitions": {
+			"node": {"type": "object", "properties": {"next": {"$ref": "#/definitions/node"}}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	dereferenced, err := schema.Dereference(DereferenceOptions{MaxDepth: 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"definitions": {
+			"node": {"type": "object", "properties": {"next": {
+				"type": "object", "properties": {"next": {"$ref": "#/definitions/node"}}
+			}}}
+		},
+		"type": "object",
+		"properties": {"next": {
+			"type": "object", "properties": {"next": {"$ref": "#/definitions/node"}}
+		}}
+	}`, string(dereferenced))
+
+	// The reference left in place resolves within the output.
+	recompiled, err := NewCompiler().Compile(dereferenced)
+	require.NoError(t, err)
+	deep := map[string]any{"next": map[string]any{"next": map[string]any{"next": 1}}}
+	assert.False(t, schema.Validate(deep).IsValid())
+	assert.False(t, recompiled.Validate(deep).IsValid())
+}
+
+func TestDereferenceOtherDialects(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"properties": {"n": {"$ref": "#/definitions/positive"}},
+		"definitions": {"positive": {"type": "number", "minimum": 0, "exclusiveMinimum": true}}
+	}`))
+	require.NoError(t, err)
+
+	dereferenced, err := schema.Dereference(DereferenceOptions{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"properties": {"n": {"type": "number", "exclusiveMinimum": 0}}
+	}`, string(dereferenced))
+}
+
+func TestDereferenceUnresolved(t *testing.T) {
+	compiler := NewCompiler()
+	delete(compiler.Loaders, "https")
+	schema, err := compiler.Compile([]byte(`{"$ref": "https://example.com/missing.json"}`))
+	require.NoError(t, err)
+
+	_, err = schema.Dereference(DereferenceOptions{})
+	require.ErrorIs(t, err, ErrUnresolvedReference)
+}
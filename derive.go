@@ -0,0 +1,97 @@
+package jsonschema
+
+import "io"
+
+// Derive returns a child compiler for variations of c, such as per-tenant
+// format assertion, extra formats or another default dialect, without
+// registering everything again.
+//
+// The child starts with the settings of c (AssertFormat, PreserveExtra,
+// DefaultBaseURI, the default dialect, JSON codecs, lockfile, schema cache
+// limit and strict mode) and can change them independently. Decoders, media
+// types, loaders, default functions, formats and RegisterFS mounts are read
+// through to c, so later registrations on c are seen by the child, while
+// registrations on the child only affect the child. The exported maps of the
+// child hold its overrides only; register nil to hide an inherited decoder,
+// media type or loader, and use UnregisterFormat to hide an inherited format.
+//
+// References the child cannot find among its own schemas are looked up in
+// the schemas compiled by c, which are shared as c compiled them. Schemas the
+// child compiles are cached on the child only. Register through the methods
+// rather than the exported maps when compilers are used concurrently.
+func (c *Compiler) Derive() *Compiler {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return &Compiler{
+		parent:         c,
+		schemas:        make(map[string]*Schema),
+		unresolvedRefs: make(map[string][]*Schema),
+		Decoders:       make(map[string]func(string) ([]byte, error)),
+		MediaTypes:     make(map[string]func([]byte) (any, error)),
+		Loaders:        make(map[string]func(url string) (io.ReadCloser, error)),
+		DefaultBaseURI: c.DefaultBaseURI,
+		AssertFormat:   c.AssertFormat,
+		PreserveExtra:  c.PreserveExtra,
+		defaultDialect: c.defaultDialect,
+		cacheLimit:     c.cacheLimit,
+		strictMode:     c.strictMode,
+		lockfile:       c.lockfile,
+		jsonEncoder:    c.jsonEncoder,
+		jsonDecoder:    c.jsonDecoder,
+		defaultFuncs:   make(map[string]DefaultFunc),
+		customFormats:  make(map[string]*FormatDef),
+	}
+}
+
+// lookup returns the entry for name in the registry of the nearest compiler
+// in the parent chain that has one.
+func lookup[V any](c *Compiler, registry func(*Compiler) map[string]V, name string) (V, bool) {
+	for ; c != nil; c = c.parent {
+		c.mu.RLock()
+		value, ok := registry(c)[name]
+		c.mu.RUnlock()
+		if ok {
+			return value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// decoder returns the content decoder for encoding.
+func (c *Compiler) decoder(encoding string) (func(string) ([]byte, error), bool) {
+	fn, _ := lookup(c, func(c *Compiler) map[string]func(string) ([]byte, error) { return c.Decoders }, encoding)
+	return fn, fn != nil
+}
+
+// mediaType returns the unmarshal function for a content media type.
+func (c *Compiler) mediaType(name string) (func([]byte) (any, error), bool) {
+	fn, _ := lookup(c, func(c *Compiler) map[string]func([]byte) (any, error) { return c.MediaTypes }, name)
+	return fn, fn != nil
+}
+
+// loader returns the loader for a URL scheme.
+func (c *Compiler) loader(scheme string) (func(string) (io.ReadCloser, error), bool) {
+	fn, _ := lookup(c, func(c *Compiler) map[string]func(string) (io.ReadCloser, error) { return c.Loaders }, scheme)
+	return fn, fn != nil
+}
+
+// defaultFunc retrieves a registered default function by name.
+func (c *Compiler) defaultFunc(name string) (DefaultFunc, bool) {
+	fn, _ := lookup(c, func(c *Compiler) map[string]DefaultFunc { return c.defaultFuncs }, name)
+	return fn, fn != nil
+}
+
+// customFormat returns the custom format registered as name, or nil.
+func (c *Compiler) customFormat(name string) *FormatDef {
+	for ; c != nil; c = c.parent {
+		c.customFormatsRW.RLock()
+		def, ok := c.customFormats[name]
+		c.customFormatsRW.RUnlock()
+		if ok {
+			return def
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveInheritsRegistries(t *testing.T) {
+	base := NewCompiler()
+	base.RegisterFormat("sku", func(v any) bool {
+		s, ok := v.(string)
+		return !ok || strings.HasPrefix(s, "SKU-")
+	}, "string")
+	base.RegisterDefaultFunc("tenant", func(...any) (any, error) { return "base", nil })
+	base.RegisterLoader("mem", func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"type": "string", "format": "sku"}`)), nil
+	})
+
+	child := base.Derive().SetAssertFormat(true)
+	schema, err := child.Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"sku": {"$ref": "mem://sku.json"},
+			"tenant": {"type": "string", "default": "tenant()"}
+		}
+	}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(map[string]any{"sku": "SKU-1"}).IsValid())
+
+	var result map[string]any
+	require.NoError(t, schema.Unmarshal(&result, map[string]any{}))
+	assert.Equal(t, "base", result["tenant"])
+
+	// The child's AssertFormat does not change the parent.
+	assert.False(t, base.AssertFormat)
+
+	// Registrations on the parent after Derive are seen by the child.
+	base.RegisterFormat("late", func(any) bool { return false })
+	late, err := child.Compile([]byte(`{"format": "late"}`))
+	require.NoError(t, err)
+	assert.False(t, late.Validate("x").IsValid())
+}
+
+func TestDeriveOverridesStayLocal(t *testing.T) {
+	base := NewCompiler().SetAssertFormat(true)
+	base.RegisterFormat("code", func(any) bool { return false })
+
+	child := base.Derive()
+	child.RegisterFormat("code", func(any) bool { return true })
+	child.RegisterFormat("extra", func(any) bool { return false })
+
+	childSchema, err := child.Compile([]byte(`{"format": "code"}`))
+	require.NoError(t, err)
+	assert.True(t, childSchema.Validate("x").IsValid())
+
+	baseSchema, err := base.Compile([]byte(`{"format": "code"}`))
+	require.NoError(t, err)
+	assert.False(t, baseSchema.Validate("x").IsValid())
+	assert.Nil(t, base.customFormat("extra"))
+
+	// Unregistering on the child hides the parent's format only there.
+	child.UnregisterFormat("code")
+	hidden, err := child.Compile([]byte(`{"format": "code"}`))
+	require.NoError(t, err)
+	assert.False(t, hidden.Validate("x").IsValid(), "unknown formats fail when asserted")
+	assert.NotNil(t, base.customFormat("code"))
+
+	// A nil loader hides the inherited one.
+	child.RegisterLoader("https", nil)
+	_, err = child.Compile([]byte(`{"$ref": "https://example.com/missing.json"}`))
+	require.NoError(t, err)
+	_, ok := child.loader("https")
+	assert.False(t, ok)
+	_, ok = base.loader("https")
+	assert.True(t, ok)
+}
+
+func TestDeriveReadsParentSchemas(t *testing.T) {
+	base := NewCompiler()
+	_, err := base.Compile([]byte(`{"$id": "https://example.com/name.json", "type": "string", "minLength": 2}`))
+	require.NoError(t, err)
+
+	child := base.Derive()
+	delete(child.Loaders, "https")
+	schema, err := child.Compile([]byte(`{"$id": "https://example.com/user.json", "properties": {"name": {"$ref": "name.json"}}}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(map[string]any{"name": "A"}).IsValid())
+
+	_, err = base.Schema("https://example.com/user.json")
+	require.Error(t, err, "schemas compiled by the child are not added to the parent")
+}
+
+func TestDeriveConcurrent(t *testing.T) {
+	base := NewCompiler()
+	base.RegisterFormat("even", func(v any) bool {
+		n, ok := v.(int)
+		return !ok || n%2 == 0
+	})
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child := base.Derive().SetAssertFormat(i%2 == 0)
+			child.RegisterFormat(fmt.Sprintf("tenant-%d", i), func(any) bool { return true })
+			schema, err := child.Compile([]byte(`{"format": "even"}`))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, i%2 != 0, schema.Validate(1).IsValid())
+		}()
+	}
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			base.RegisterDecoder("hex", func(string) ([]byte, error) { return nil, nil })
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDeriveConcurrentMounts(t *testing.T) {
+	base := NewCompiler()
+	base.RegisterFS("https://schemas.example.com/", fstest.MapFS{
+		"name.json": {Data: []byte(`{"type": "string"}`)},
+	})
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			schema, err := base.Derive().Compile([]byte(`{"$ref": "https://schemas.example.com/name.json"}`))
+			if assert.NoError(t, err) {
+				assert.False(t, schema.Validate(1).IsValid())
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			base.RegisterFS(fmt.Sprintf("https://tenant-%d.example.com/", i), fstest.MapFS{})
+		}()
+	}
+	wg.Wait()
+}
@@ -3,6 +3,8 @@ package jsonschema
 import (
 	"bytes"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/go-json-experiment/json"
@@ -27,6 +29,12 @@ const (
 	Draft6 Dialect = "http://json-schema.org/draft-06/schema#"
 	// Draft4 identifies JSON Schema Draft-04.
 	Draft4 Dialect = "http://json-schema.org/draft-04/schema#"
+	// Draft3 identifies JSON Schema Draft-03.
+	Draft3 Dialect = "http://json-schema.org/draft-03/schema#"
+	// OpenAPI30 identifies the OpenAPI 3.0 Schema Object, an extended subset
+	// of Draft-04 (Draft Wright-00). OpenAPI 3.0 schemas do not declare
+	// "$schema", so select it with Compiler.SetDefaultDialect.
+	OpenAPI30 Dialect = "https://spec.openapis.org/oas/3.0/schema/2021-09-28#/definitions/Schema"
 )
 
 // SetDefaultDialect sets the dialect used when a schema resource does not
@@ -91,9 +99,7 @@ func (c *Compiler) schemaHasValidationVocabulary(schemaURI string) bool {
 		return true
 	}
 
-	c.mu.RLock()
-	metaschema := c.schemas[schemaURI]
-	c.mu.RUnlock()
+	metaschema, _ := c.cachedSchema(schemaURI)
 	if metaschema == nil || len(metaschema.Vocabulary) == 0 {
 		return true
 	}
@@ -113,6 +119,10 @@ func dialectFromSchemaURI(uri string, fallback Dialect) Dialect {
 		return Draft6
 	case "https://json-schema.org/draft-04/schema", "http://json-schema.org/draft-04/schema":
 		return Draft4
+	case "https://json-schema.org/draft-03/schema", "http://json-schema.org/draft-03/schema":
+		return Draft3
+	case string(OpenAPI30):
+		return OpenAPI30
 	default:
 		return fallback
 	}
@@ -120,8 +130,9 @@ func dialectFromSchemaURI(uri string, fallback Dialect) Dialect {
 
 // applyDialectCompatibility binds dialect-specific keywords parked in rawExtra
 // according to the resolved dialect, applies Draft-04 boolean exclusive bounds,
-// then promotes whatever the dialect did not claim to Extra. A keyword the active
-// dialect does not recognize is, by definition, an extension for that dialect.
+// the Draft-03 keywords and OpenAPI 3.0 "nullable", then promotes whatever the dialect did not claim
+// to Extra. A keyword the active dialect does not recognize is, by definition,
+// an extension for that dialect.
 func (s *Schema) applyDialectCompatibility() error {
 	if err := s.claimLegacyKeywords(); err != nil {
 		return err
@@ -129,6 +140,12 @@ func (s *Schema) applyDialectCompatibility() error {
 	if err := s.applyLegacyExclusiveBounds(); err != nil {
 		return err
 	}
+	if err := s.applyDraft3Keywords(); err != nil {
+		return err
+	}
+	if err := s.applyOpenAPI30Keywords(); err != nil {
+		return err
+	}
 	return s.finalizeExtra()
 }
 
@@ -140,8 +157,8 @@ func (s *Schema) claimLegacyKeywords() error {
 		return nil
 	}
 
-	// "id" is the Draft-04 spelling of "$id" ("$id" arrived in Draft-06).
-	if raw, ok := s.rawExtra["id"]; ok && s.dialect == Draft4 {
+	// "id" is the Draft-03/04 spelling of "$id" ("$id" arrived in Draft-06).
+	if raw, ok := s.rawExtra["id"]; ok && (s.dialect == Draft4 || s.dialect == Draft3) {
 		var id string
 		if err := json.Unmarshal(raw, &id); err != nil {
 			return fmt.Errorf("id: %w", err)
@@ -213,7 +230,7 @@ func (s *Schema) finalizeExtra() error {
 
 func (s *Schema) applyLegacyExclusiveBounds() error {
 	if len(s.legacyExclusiveMinimum) > 0 {
-		if s.dialect != Draft4 {
+		if !s.dialect.usesBooleanExclusiveBounds() {
 			return fmt.Errorf("exclusiveMinimum: %w", ErrUnsupportedRatType)
 		}
 		if isJSONTrue(s.legacyExclusiveMinimum) && s.Minimum != nil {
@@ -223,7 +240,7 @@ func (s *Schema) applyLegacyExclusiveBounds() error {
 	}
 
 	if len(s.legacyExclusiveMaximum) > 0 {
-		if s.dialect != Draft4 {
+		if !s.dialect.usesBooleanExclusiveBounds() {
 			return fmt.Errorf("exclusiveMaximum: %w", ErrUnsupportedRatType)
 		}
 		if isJSONTrue(s.legacyExclusiveMaximum) && s.Maximum != nil {
@@ -234,6 +251,122 @@ func (s *Schema) applyLegacyExclusiveBounds() error {
 	return nil
 }
 
+// applyDraft3Keywords rewrites the Draft-03 keywords into their later
+// equivalents: "divisibleBy" becomes "multipleOf", "extends" and type unions
+// listing schemas become "allOf" entries, "disallow" a negated one, and
+// "required": true on a property an entry of the parent's "required".
+func (s *Schema) applyDraft3Keywords() error {
+	if s.dialect != Draft3 {
+		if len(s.legacyType) > 0 {
+			return fmt.Errorf("type: %w", ErrInvalidSchemaType)
+		}
+		if len(s.legacyRequired) > 0 {
+			return fmt.Errorf("required: %w", ErrInvalidSchemaType)
+		}
+		return nil
+	}
+
+	if slices.Contains(s.Type, "any") {
+		s.Type = nil
+	}
+	if len(s.legacyType) > 0 {
+		union, err := draft3TypeUnion(s.legacyType)
+		if err != nil {
+			return fmt.Errorf("type: %w", err)
+		}
+		s.AllOf = append(s.AllOf, union)
+	}
+
+	if raw, ok := s.rawExtra["divisibleBy"]; ok {
+		divisor := &Rat{}
+		if err := json.Unmarshal(raw, divisor); err != nil {
+			return fmt.Errorf("divisibleBy: %w", err)
+		}
+		if s.MultipleOf == nil {
+			s.MultipleOf = divisor
+		}
+		delete(s.rawExtra, "divisibleBy")
+	}
+
+	if raw, ok := s.rawExtra["extends"]; ok {
+		var extends []*Schema
+		var err error
+		if raw.Kind() == '[' {
+			err = json.Unmarshal(raw, &extends)
+		} else {
+			single := &Schema{}
+			err = json.Unmarshal(raw, single)
+			extends = []*Schema{single}
+		}
+		if err != nil {
+			return fmt.Errorf("extends: %w", err)
+		}
+		s.AllOf = append(s.AllOf, extends...)
+		delete(s.rawExtra, "extends")
+	}
+
+	if raw, ok := s.rawExtra["disallow"]; ok {
+		union, err := draft3TypeUnion(raw)
+		if err != nil {
+			return fmt.Errorf("disallow: %w", err)
+		}
+		s.AllOf = append(s.AllOf, &Schema{Not: union})
+		delete(s.rawExtra, "disallow")
+	}
+
+	if s.Properties != nil {
+		for _, name := range slices.Sorted(maps.Keys(*s.Properties)) {
+			if property := (*s.Properties)[name]; property != nil && isJSONTrue(property.legacyRequired) &&
+				!slices.Contains(s.Required, name) {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+	return nil
+}
+
+// draft3TypeUnion returns a schema accepting the values of a Draft-03 type
+// union: a type name, or a list of type names and schemas where "any"
+// accepts everything.
+func draft3TypeUnion(raw jsontext.Value) (*Schema, error) {
+	var members []jsontext.Value
+	if raw.Kind() == '[' {
+		if err := json.Unmarshal(raw, &members); err != nil {
+			return nil, err
+		}
+	} else {
+		members = []jsontext.Value{raw}
+	}
+
+	var types SchemaType
+	var branches []*Schema
+	for _, member := range members {
+		if member.Kind() == '"' {
+			var name string
+			if err := json.Unmarshal(member, &name); err != nil {
+				return nil, err
+			}
+			if name == "any" {
+				return &Schema{}, nil
+			}
+			types = append(types, name)
+			continue
+		}
+		branch := &Schema{}
+		if err := json.Unmarshal(member, branch); err != nil {
+			return nil, err
+		}
+		branches = append(branches, branch)
+	}
+	if len(branches) == 0 {
+		return &Schema{Type: types}, nil
+	}
+	if len(types) > 0 {
+		branches = append([]*Schema{{Type: types}}, branches...)
+	}
+	return &Schema{AnyOf: branches}, nil
+}
+
 func (s *Schema) applyLegacyDependencies(rawDependencies jsontext.Value) error {
 	var dependencies map[string]jsontext.Value
 	if err := json.Unmarshal(rawDependencies, &dependencies); err != nil {
@@ -246,6 +379,18 @@ func (s *Schema) applyLegacyDependencies(rawDependencies jsontext.Value) error {
 			continue
 		}
 
+		if trimmed[0] == '"' && s.dialect == Draft3 {
+			// Draft-03 names a single required property as a string.
+			var required string
+			if err := json.Unmarshal(raw, &required); err != nil {
+				return fmt.Errorf("dependencies %q: %w", property, err)
+			}
+			if s.DependentRequired == nil {
+				s.DependentRequired = make(map[string][]string)
+			}
+			s.DependentRequired[property] = []string{required}
+			continue
+		}
 		if trimmed[0] == '[' {
 			var required []string
 			if err := json.Unmarshal(raw, &required); err != nil {
@@ -272,7 +417,7 @@ func (s *Schema) applyLegacyDependencies(rawDependencies jsontext.Value) error {
 
 func (d Dialect) supportsLegacyDependencies() bool {
 	switch d {
-	case Draft201909, Draft7, Draft6, Draft4:
+	case Draft201909, Draft7, Draft6, Draft4, Draft3:
 		return true
 	default:
 		return false
@@ -281,7 +426,7 @@ func (d Dialect) supportsLegacyDependencies() bool {
 
 func (d Dialect) usesLegacyTupleItems() bool {
 	switch d {
-	case Draft201909, Draft7, Draft6, Draft4:
+	case Draft201909, Draft7, Draft6, Draft4, Draft3:
 		return true
 	default:
 		return false
@@ -290,7 +435,7 @@ func (d Dialect) usesLegacyTupleItems() bool {
 
 func (d Dialect) refIgnoresSiblings() bool {
 	switch d {
-	case Draft7, Draft6, Draft4:
+	case Draft7, Draft6, Draft4, Draft3, OpenAPI30:
 		return true
 	default:
 		return false
@@ -299,7 +444,16 @@ func (d Dialect) refIgnoresSiblings() bool {
 
 func (d Dialect) supportsLegacyIDAnchors() bool {
 	switch d {
-	case Draft7, Draft6, Draft4:
+	case Draft7, Draft6, Draft4, Draft3:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d Dialect) usesBooleanExclusiveBounds() bool {
+	switch d {
+	case Draft4, Draft3, OpenAPI30:
 		return true
 	default:
 		return false
@@ -0,0 +1,72 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraft3Keywords(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$schema": "http://json-schema.org/draft-03/schema#",
+		"id": "https://example.com/partner.json",
+		"type": "object",
+		"extends": {"properties": {"kind": {"type": "string", "required": true}}},
+		"properties": {
+			"id": {"type": "integer", "required": true, "divisibleBy": 5},
+			"note": {"type": ["null", {"type": "string", "maxLength": 3}]},
+			"tag": {"type": "any", "disallow": ["boolean", {"type": "string", "pattern": "^x"}]}
+		},
+		"dependencies": {"tag": "note"}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, Draft3, schema.Dialect())
+	assert.Equal(t, "https://example.com/partner.json", schema.ID)
+	assert.Equal(t, []string{"id"}, schema.Required)
+
+	tests := []struct {
+		name     string
+		instance map[string]any
+		valid    bool
+	}{
+		{"valid", map[string]any{"kind": "a", "id": 10, "note": "abc", "tag": 1}, true},
+		{"missing required property", map[string]any{"kind": "a"}, false},
+		{"missing extended required property", map[string]any{"id": 10}, false},
+		{"not divisible", map[string]any{"kind": "a", "id": 7}, false},
+		{"type union with a schema", map[string]any{"kind": "a", "id": 10, "note": nil}, true},
+		{"type union schema fails", map[string]any{"kind": "a", "id": 10, "note": "abcd"}, false},
+		{"disallowed type", map[string]any{"kind": "a", "id": 10, "note": "a", "tag": true}, false},
+		{"disallowed schema", map[string]any{"kind": "a", "id": 10, "note": "a", "tag": "xy"}, false},
+		{"allowed by disallow", map[string]any{"kind": "a", "id": 10, "note": "a", "tag": "y"}, true},
+		{"string dependency", map[string]any{"kind": "a", "id": 10, "tag": 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.valid, schema.Validate(tt.instance).IsValid())
+		})
+	}
+}
+
+func TestDraft3KeywordsRejectedByLaterDialects(t *testing.T) {
+	_, err := NewCompiler().Compile([]byte(`{"type": ["null", {"type": "string"}]}`))
+	require.ErrorIs(t, err, ErrInvalidSchemaType)
+	_, err = NewCompiler().Compile([]byte(`{"properties": {"id": {"required": true}}}`))
+	require.ErrorIs(t, err, ErrInvalidSchemaType)
+
+	schema, err := NewCompiler().SetPreserveExtra(true).Compile([]byte(`{"divisibleBy": 2, "extends": {"type": "string"}}`))
+	require.NoError(t, err)
+	assert.Contains(t, schema.Extra, "divisibleBy", "Draft-03 keywords are extensions in later dialects")
+	assert.True(t, schema.Validate(3).IsValid())
+}
+
+func TestDraft3MetaSchema(t *testing.T) {
+	compiler := NewCompiler().SetDefaultDialect(Draft3)
+	result, err := compiler.ValidateSchema([]byte(`{"type": ["string", {"type": "integer"}], "properties": {"a": {"required": true}}, "dependencies": {"a": "b"}}`))
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+
+	result, err = compiler.ValidateSchema([]byte(`{"required": ["a"], "divisibleBy": 0}`))
+	require.NoError(t, err)
+	assert.False(t, result.IsValid())
+}
@@ -0,0 +1,147 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI30Keywords(t *testing.T) {
+	compiler := NewCompiler().SetDefaultDialect(OpenAPI30).SetPreserveExtra(true)
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"required": ["id"],
+		"discriminator": {"propertyName": "kind"},
+		"x-internal": true,
+		"properties": {
+			"id": {"type": "integer", "format": "int32", "minimum": 0, "exclusiveMinimum": true},
+			"kind": {"type": "string", "enum": ["a", "b"], "nullable": true},
+			"note": {"type": "string", "nullable": true, "example": "hello"},
+			"pet": {"$ref": "#/properties/note", "type": "integer"},
+			"any": {"nullable": true}
+		}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, OpenAPI30, schema.Dialect())
+	assert.Contains(t, schema.Extra, "x-internal")
+	assert.Contains(t, schema.Extra, "discriminator")
+	assert.Equal(t, SchemaType{"string", "null"}, (*schema.Properties)["note"].Type)
+	assert.NotContains(t, (*schema.Properties)["note"].Extra, "nullable")
+
+	tests := []struct {
+		name     string
+		instance map[string]any
+		valid    bool
+	}{
+		{"valid", map[string]any{"id": 1, "kind": "a", "note": "x", "pet": "y"}, true},
+		{"nullable type", map[string]any{"id": 1, "note": nil}, true},
+		{"enum without null", map[string]any{"id": 1, "kind": nil}, false},
+		{"boolean exclusive minimum", map[string]any{"id": 0}, false},
+		{"int32 range", map[string]any{"id": 2147483648}, false},
+		{"siblings of $ref are ignored", map[string]any{"id": 1, "pet": "y"}, true},
+		{"$ref target applies", map[string]any{"id": 1, "pet": 2}, false},
+		{"nullable without type", map[string]any{"id": 1, "any": nil}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.valid, schema.Validate(tt.instance).IsValid())
+		})
+	}
+}
+
+func TestOpenAPI30NumericFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		value  any
+		valid  bool
+	}{
+		{"int32", 2147483647, true},
+		{"int32", -2147483648, true},
+		{"int32", 2147483648, false},
+		{"int32", -2147483649, false},
+		{"int32", 1.5, false},
+		{"int64", json.Number("9223372036854775807"), true},
+		{"int64", json.Number("9223372036854775808"), false},
+		{"int64", json.Number("-9223372036854775809"), false},
+		{"float", json.Number("340282346638528859811704183484516925440"), true},
+		{"float", json.Number("340282346638528859811704183484516925441"), false},
+		{"float", 3.5e38, false},
+		{"float", -3.5e38, false},
+		{"float", 0.1, true},
+		{"double", 1.7976931348623157e308, true},
+		{"double", json.Number("1.8e308"), false},
+		{"int32", "not a number", true},
+	}
+	for _, tt := range tests {
+		schema, err := NewCompiler().SetDefaultDialect(OpenAPI30).Compile([]byte(`{"format": "` + tt.format + `"}`))
+		require.NoError(t, err)
+		assert.Equal(t, tt.valid, schema.Validate(tt.value).IsValid(), "%s %v", tt.format, tt.value)
+	}
+
+	schema, err := NewCompiler().Compile([]byte(`{"format": "int32"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(2147483648).IsValid(), "other dialects leave int32 unknown")
+}
+
+func TestOpenAPI30StringFormats(t *testing.T) {
+	compiler := NewCompiler().SetDefaultDialect(OpenAPI30)
+	schema, err := compiler.Compile([]byte(`{"type": "string", "format": "byte"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("not base64!").IsValid(), "string formats are annotations by default")
+
+	compiler = NewCompiler().SetDefaultDialect(OpenAPI30).SetAssertFormat(true)
+	for format, tests := range map[string]map[string]bool{
+		"byte":     {"aGVsbG8=": true, "not base64!": false},
+		"binary":   {"\x00\x01": true},
+		"password": {"secret": true},
+	} {
+		schema, err := compiler.Compile([]byte(`{"type": "string", "format": "` + format + `"}`))
+		require.NoError(t, err)
+		for value, valid := range tests {
+			assert.Equal(t, valid, schema.Validate(value).IsValid(), "%s %q", format, value)
+		}
+	}
+}
+
+func TestOpenAPI30MetaSchema(t *testing.T) {
+	compiler := NewCompiler().SetDefaultDialect(OpenAPI30)
+	for _, source := range []string{
+		`{"type": "string", "nullable": true, "format": "byte", "example": "aGk=", "x-go-type": "[]byte"}`,
+		`{"type": "object", "discriminator": {"propertyName": "kind", "mapping": {"a": "#/components/schemas/A"}}, "oneOf": [{"$ref": "#/components/schemas/A"}]}`,
+		`{"type": "number", "minimum": 0, "exclusiveMinimum": true, "xml": {"name": "n", "attribute": true}}`,
+		`{"$ref": "#/components/schemas/A"}`,
+	} {
+		result, err := compiler.ValidateSchema([]byte(source))
+		require.NoError(t, err)
+		assert.True(t, result.IsValid(), source)
+	}
+
+	for _, source := range []string{
+		`{"type": ["string", "null"]}`,
+		`{"type": "null"}`,
+		`{"const": 1}`,
+		`{"items": [{"type": "string"}]}`,
+		`{"nullable": "yes"}`,
+		`{"discriminator": {}}`,
+	} {
+		result, err := compiler.ValidateSchema([]byte(source))
+		require.NoError(t, err)
+		assert.False(t, result.IsValid(), source)
+	}
+}
+
+func TestOpenAPI30StrictMode(t *testing.T) {
+	_, err := NewCompiler().SetDefaultDialect(OpenAPI30).SetStrictMode(StrictModeError).
+		Compile([]byte(`{"type": "string", "nullable": true, "example": "a", "x-extra": 1}`))
+	require.NoError(t, err)
+
+	_, err = NewCompiler().SetDefaultDialect(OpenAPI30).SetStrictMode(StrictModeError).
+		Compile([]byte(`{"type": "string", "const": "a"}`))
+	var strictErr *StrictError
+	require.ErrorAs(t, err, &strictErr)
+	require.Len(t, strictErr.Violations, 1)
+	assert.Equal(t, StrictDialectKeyword, strictErr.Violations[0].Rule)
+	assert.Equal(t, "const", strictErr.Violations[0].Keyword)
+}
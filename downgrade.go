@@ -0,0 +1,314 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// DowngradeOptions configures Schema.Downgrade.
+type DowngradeOptions struct {
+	// Approximate replaces constructs Draft-07 cannot express with the
+	// closest Draft-07 equivalent instead of failing. The returned issues
+	// describe each approximation.
+	Approximate bool
+}
+
+// Downgrade returns the document s belongs to as a Draft-07 document, for
+// consumers that only understand Draft-07:
+//
+//   - "$defs" becomes "definitions", and "$ref" pointers follow;
+//   - "prefixItems" becomes array-form "items" and "items" becomes
+//     "additionalItems";
+//   - "dependentRequired" and "dependentSchemas" become "dependencies";
+//   - "$anchor" becomes a plain-name fragment in "$id";
+//   - a "$ref" with sibling keywords moves into "allOf", since Draft-07
+//     ignores the siblings.
+//
+// "unevaluatedProperties" and "unevaluatedItems" become
+// "additionalProperties" and "items" when no other subschema evaluates the
+// same instance. Where Draft-07 has no equivalent, as for dynamic references,
+// "minContains" and "maxContains", Downgrade fails with ErrLossyDowngrade
+// listing the constructs, unless opts.Approximate is set: "$dynamicRef" then
+// becomes a static "$ref" and the other constructs are dropped, which makes
+// the schema accept more.
+func (s *Schema) Downgrade(opts DowngradeOptions) ([]byte, []MigrationIssue, error) {
+	data, err := s.rootSchema().MarshalJSON()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+	}
+	return downgradeDocument(data, opts)
+}
+
+// downgradeDocument rewrites data, a document in the Draft 2020-12 form
+// MarshalJSON writes, as a Draft-07 document.
+func downgradeDocument(data []byte, opts DowngradeOptions) ([]byte, []MigrationIssue, error) {
+	document, err := decodeOrderedJSON(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+	}
+
+	d := &downgrader{}
+	d.downgrade(document, "")
+	if root, ok := document.(*orderedObject); ok {
+		fixReferences(root)
+		if _, declared := root.get("$schema"); !declared {
+			root.insert(0, "$schema", jsontext.Value(strconv.Quote(string(Draft7))))
+		}
+	}
+	if len(d.issues) > 0 && !opts.Approximate {
+		messages := make([]string, len(d.issues))
+		for i, issue := range d.issues {
+			messages[i] = issue.String()
+		}
+		return nil, d.issues, fmt.Errorf("%w: %s", ErrLossyDowngrade, strings.Join(messages, "; "))
+	}
+
+	var buf bytes.Buffer
+	if err := encodeOrderedJSON(jsontext.NewEncoder(&buf), document); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), d.issues, nil
+}
+
+// downgrader rewrites a compiled document for Draft-07.
+type downgrader struct {
+	issues []MigrationIssue
+}
+
+func (d *downgrader) report(location, keyword, format string, args ...any) {
+	d.issues = append(d.issues, MigrationIssue{Location: location, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+}
+
+// Keywords whose subschemas evaluate properties or items of the instance the
+// schema applies to, so "unevaluated*" keywords depend on them.
+var (
+	inPlaceApplicators = []string{"$ref", "$dynamicRef", "allOf", "anyOf", "oneOf", "if", "then", "else"}
+	propertyEvaluators = append([]string{"dependentSchemas"}, inPlaceApplicators...)
+	itemEvaluators     = append([]string{"contains"}, inPlaceApplicators...)
+)
+
+// downgrade rewrites the schema value at location and its subschemas.
+func (d *downgrader) downgrade(value any, location string) {
+	object, ok := value.(*orderedObject)
+	if !ok {
+		return
+	}
+	keywordLocation := func(keyword string) string {
+		return location + "/" + pointerEscaper.Replace(keyword)
+	}
+	for _, member := range object.members {
+		switch {
+		case slices.Contains(schemaMapKeywords, member.name):
+			if members, isObject := member.value.(*orderedObject); isObject {
+				for _, child := range members.members {
+					d.downgrade(child.value, keywordLocation(member.name)+"/"+pointerEscaper.Replace(child.name))
+				}
+			}
+		case slices.Contains(schemaArrayKeywords, member.name):
+			if elements, isArray := member.value.([]any); isArray {
+				for i, element := range elements {
+					d.downgrade(element, fmt.Sprintf("%s/%d", keywordLocation(member.name), i))
+				}
+				continue
+			}
+			d.downgrade(member.value, keywordLocation(member.name))
+		case slices.Contains(schemaKeywords, member.name):
+			d.downgrade(member.value, keywordLocation(member.name))
+		}
+	}
+
+	if _, ok := object.get("$schema"); ok {
+		object.set("$schema", jsontext.Value(strconv.Quote(string(Draft7))))
+	}
+	if _, ok := object.get("$vocabulary"); ok {
+		object.remove("$vocabulary")
+		d.report(location, "$vocabulary", "$vocabulary has no Draft-07 equivalent and is dropped")
+	}
+
+	d.unevaluated(object, location)
+	d.contains(object, location)
+	d.identifiers(object, location)
+
+	if defs, ok := object.get("$defs"); ok {
+		object.replace("$defs", "definitions", defs)
+	}
+	if prefixItems, ok := object.get("prefixItems"); ok {
+		if items, hasItems := object.get("items"); hasItems {
+			object.replace("items", "additionalItems", items)
+		}
+		object.replace("prefixItems", "items", prefixItems)
+	}
+	d.dependencies(object)
+	d.references(object, location)
+}
+
+// unevaluated rewrites "unevaluatedProperties" and "unevaluatedItems" as
+// "additionalProperties" and "items" when nothing else evaluates the
+// instance, and drops them when they can never apply.
+func (d *downgrader) unevaluated(object *orderedObject, location string) {
+	hasAny := func(keywords []string) bool {
+		return slices.ContainsFunc(keywords, func(keyword string) bool {
+			_, ok := object.get(keyword)
+			return ok
+		})
+	}
+
+	if unevaluated, ok := object.get("unevaluatedProperties"); ok {
+		switch _, hasAdditional := object.get("additionalProperties"); {
+		case hasAdditional:
+			object.remove("unevaluatedProperties") // Every property is already evaluated.
+		case !hasAny(propertyEvaluators):
+			object.replace("unevaluatedProperties", "additionalProperties", unevaluated)
+		default:
+			object.remove("unevaluatedProperties")
+			d.report(location, "unevaluatedProperties", "unevaluatedProperties depends on subschemas Draft-07 cannot see into and is dropped")
+		}
+	}
+
+	if unevaluated, ok := object.get("unevaluatedItems"); ok {
+		switch _, hasItems := object.get("items"); {
+		case hasItems:
+			object.remove("unevaluatedItems") // Every item is already evaluated.
+		case !hasAny(itemEvaluators):
+			object.replace("unevaluatedItems", "items", unevaluated)
+		default:
+			object.remove("unevaluatedItems")
+			d.report(location, "unevaluatedItems", "unevaluatedItems depends on subschemas Draft-07 cannot see into and is dropped")
+		}
+	}
+}
+
+// contains drops "minContains" and "maxContains", exactly when minContains
+// is 0 without a maxContains and "contains" goes with it.
+func (d *downgrader) contains(object *orderedObject, location string) {
+	if raw, ok := object.get("minContains"); ok {
+		object.remove("minContains")
+		_, hasMax := object.get("maxContains")
+		if value, isScalar := raw.(jsontext.Value); isScalar && isZeroNumber(value) && !hasMax {
+			object.remove("contains") // Any array holds zero matching items.
+		} else {
+			d.report(location, "minContains", "minContains has no Draft-07 equivalent and is dropped")
+		}
+	}
+	if _, ok := object.get("maxContains"); ok {
+		object.remove("maxContains")
+		d.report(location, "maxContains", "maxContains has no Draft-07 equivalent and is dropped")
+	}
+}
+
+// identifiers rewrites "$anchor" and "$dynamicAnchor" as plain-name
+// fragments of "$id".
+func (d *downgrader) identifiers(object *orderedObject, location string) {
+	anchor, _ := object.get("$anchor")
+	name, hasAnchor := jsonString(anchor)
+	if dynamic, ok := object.get("$dynamicAnchor"); ok {
+		object.remove("$dynamicAnchor")
+		dynamicName, _ := jsonString(dynamic)
+		switch {
+		case dynamicName == recursiveDynamicAnchor:
+			d.report(location, "$recursiveAnchor", "$recursiveAnchor has no Draft-07 equivalent and is dropped")
+		case hasAnchor:
+			d.report(location, "$dynamicAnchor", "$dynamicAnchor has no Draft-07 equivalent and is dropped")
+		default:
+			d.report(location, "$dynamicAnchor", "$dynamicAnchor has no Draft-07 equivalent and becomes a plain-name $id")
+			name, hasAnchor = dynamicName, true
+			object.set("$anchor", jsontext.Value(strconv.Quote(name)))
+		}
+	}
+	if !hasAnchor {
+		return
+	}
+
+	id := "#" + name
+	if raw, ok := object.get("$id"); ok {
+		base, _ := jsonString(raw)
+		id = strings.TrimSuffix(base, "#") + id
+		object.remove("$anchor")
+		object.set("$id", jsontext.Value(strconv.Quote(id)))
+		return
+	}
+	object.replace("$anchor", "$id", jsontext.Value(strconv.Quote(id)))
+}
+
+// dependencies merges "dependentRequired" and "dependentSchemas" into
+// "dependencies", in place of the first. A property listed by both depends
+// on a schema requiring the names and applying the subschema.
+func (d *downgrader) dependencies(object *orderedObject) {
+	merged := &orderedObject{}
+	index := -1
+	for _, keyword := range []string{"dependentRequired", "dependentSchemas"} {
+		raw, ok := object.get(keyword)
+		if !ok {
+			continue
+		}
+		if index < 0 {
+			index = object.index(keyword)
+		}
+		if members, isObject := raw.(*orderedObject); isObject {
+			for _, member := range members.members {
+				if names, shared := merged.get(member.name); shared {
+					member.value = &orderedObject{members: []orderedMember{
+						{name: "required", value: names},
+						{name: "allOf", value: []any{member.value}},
+					}}
+				}
+				merged.set(member.name, member.value)
+			}
+		}
+		object.remove(keyword)
+		object.rename(keyword, "dependencies")
+	}
+	if index >= 0 {
+		object.insert(index, "dependencies", merged)
+	}
+}
+
+// references rewrites "$dynamicRef" as "$ref", and moves a "$ref" with
+// sibling keywords into "allOf", which Draft-07 does not ignore.
+func (d *downgrader) references(object *orderedObject, location string) {
+	if dynamic, ok := object.get("$dynamicRef"); ok {
+		object.remove("$dynamicRef")
+		d.report(location, "$dynamicRef", "$dynamicRef has no Draft-07 equivalent and becomes a static $ref")
+		if ref, hasRef := object.get("$ref"); hasRef {
+			object.remove("$ref")
+			d.appendAllOf(object, ref)
+		}
+		object.set("$ref", dynamic)
+	}
+
+	ref, ok := object.get("$ref")
+	if !ok {
+		return
+	}
+	for _, member := range object.members {
+		switch member.name {
+		case "$ref", "$schema", "$comment", "title", "description", "default", "examples", "readOnly", "writeOnly", "definitions":
+		default:
+			object.remove("$ref")
+			d.appendAllOf(object, ref)
+			return
+		}
+	}
+}
+
+// appendAllOf adds {"$ref": ref} to the "allOf" of object.
+func (d *downgrader) appendAllOf(object *orderedObject, ref any) {
+	wrapper := &orderedObject{members: []orderedMember{{name: "$ref", value: ref}}}
+	if allOf, ok := object.get("allOf"); ok {
+		if elements, isArray := allOf.([]any); isArray {
+			object.set("allOf", append(elements, wrapper))
+			return
+		}
+	}
+	object.set("allOf", []any{wrapper})
+}
+
+func isZeroNumber(raw jsontext.Value) bool {
+	number, ok := parseRat(string(raw))
+	return ok && number.Sign() == 0
+}
@@ -0,0 +1,171 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaDowngrade(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "definitions, tuples and dependencies",
+			source:   `{"prefixItems": [{"$ref": "#/$defs/a"}], "items": {"$ref": "#/prefixItems/0"}, "dependentRequired": {"x": ["y"]}, "dependentSchemas": {"y": {"required": ["z"]}}, "$defs": {"a": {"type": "string"}}}`,
+			expected: `{"$schema": "http://json-schema.org/draft-07/schema#", "items": [{"$ref": "#/definitions/a"}], "additionalItems": {"$ref": "#/items/0"}, "dependencies": {"x": ["y"], "y": {"required": ["z"]}}, "definitions": {"a": {"type": "string"}}}`,
+		},
+		{
+			name:     "anchors become plain-name identifiers",
+			source:   `{"$id": "https://example.com/root.json", "$defs": {"a": {"$anchor": "a"}, "b": {"$id": "b.json", "$anchor": "b"}}}`,
+			expected: `{"$schema": "http://json-schema.org/draft-07/schema#", "$id": "https://example.com/root.json", "definitions": {"a": {"$id": "#a"}, "b": {"$id": "b.json#b"}}}`,
+		},
+		{
+			name:     "siblings of $ref move into allOf",
+			source:   `{"$ref": "#/$defs/a", "title": "A", "type": "object", "$defs": {"a": {}}}`,
+			expected: `{"$schema": "http://json-schema.org/draft-07/schema#", "allOf": [{"$ref": "#/definitions/a"}], "title": "A", "type": "object", "definitions": {"a": {}}}`,
+		},
+		{
+			name:     "unevaluated keywords without other evaluators",
+			source:   `{"properties": {"a": {}}, "unevaluatedProperties": false, "prefixItems": [{}], "unevaluatedItems": false}`,
+			expected: `{"$schema": "http://json-schema.org/draft-07/schema#", "properties": {"a": {}}, "additionalProperties": false, "items": [{}], "additionalItems": false}`,
+		},
+		{
+			name:     "unevaluated keywords with no effect",
+			source:   `{"additionalProperties": true, "unevaluatedProperties": false, "items": {}, "unevaluatedItems": false, "contains": {"type": "string"}, "minContains": 0}`,
+			expected: `{"$schema": "http://json-schema.org/draft-07/schema#", "additionalProperties": true, "items": {}}`,
+		},
+		{
+			name:     "dependencies shared by both keywords",
+			source:   `{"dependentRequired": {"a": ["b"]}, "dependentSchemas": {"a": {"required": ["c"]}}}`,
+			expected: `{"$schema": "http://json-schema.org/draft-07/schema#", "dependencies": {"a": {"required": ["b"], "allOf": [{"required": ["c"]}]}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewCompiler().Compile([]byte(tt.source))
+			require.NoError(t, err)
+
+			downgraded, issues, err := schema.Downgrade(DowngradeOptions{})
+			require.NoError(t, err)
+			assert.Empty(t, issues)
+			assert.JSONEq(t, tt.expected, string(downgraded))
+		})
+	}
+}
+
+func TestSchemaDowngradeLossy(t *testing.T) {
+	source := `{
+		"$id": "https://example.com/tree.json",
+		"$dynamicAnchor": "node",
+		"type": "object",
+		"properties": {
+			"children": {"type": "array", "items": {"$dynamicRef": "#node"}, "contains": {}, "maxContains": 3},
+			"meta": {"allOf": [{"properties": {"a": {}}}], "unevaluatedProperties": false}
+		}
+	}`
+	schema, err := NewCompiler().Compile([]byte(source))
+	require.NoError(t, err)
+
+	expectedIssues := []MigrationIssue{
+		{Location: "/properties/children/items", Keyword: "$dynamicRef", Message: "$dynamicRef has no Draft-07 equivalent and becomes a static $ref"},
+		{Location: "/properties/children", Keyword: "maxContains", Message: "maxContains has no Draft-07 equivalent and is dropped"},
+		{Location: "/properties/meta", Keyword: "unevaluatedProperties", Message: "unevaluatedProperties depends on subschemas Draft-07 cannot see into and is dropped"},
+		{Location: "", Keyword: "$dynamicAnchor", Message: "$dynamicAnchor has no Draft-07 equivalent and becomes a plain-name $id"},
+	}
+
+	downgraded, issues, err := schema.Downgrade(DowngradeOptions{})
+	require.ErrorIs(t, err, ErrLossyDowngrade)
+	assert.Nil(t, downgraded)
+	assert.Equal(t, expectedIssues, issues)
+
+	downgraded, issues, err = schema.Downgrade(DowngradeOptions{Approximate: true})
+	require.NoError(t, err)
+	assert.Equal(t, expectedIssues, issues)
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id": "https://example.com/tree.json#node",
+		"type": "object",
+		"properties": {
+			"children": {"type": "array", "items": {"$ref": "#node"}, "contains": {}},
+			"meta": {"allOf": [{"properties": {"a": {}}}]}
+		}
+	}`, string(downgraded))
+
+	approximated, err := NewCompiler().Compile(downgraded)
+	require.NoError(t, err)
+	assert.Equal(t, Draft7, approximated.Dialect())
+	assert.True(t, approximated.Validate(map[string]any{"children": []any{map[string]any{}}}).IsValid())
+	assert.False(t, approximated.Validate(map[string]any{"children": []any{1}}).IsValid())
+}
+
+func TestSchemaDowngradeKeepsValidation(t *testing.T) {
+	source := `{
+		"type": "object",
+		"properties": {
+			"total": {"type": "number", "exclusiveMinimum": 0},
+			"lines": {"type": "array", "prefixItems": [{"$ref": "#/$defs/sku"}], "items": false}
+		},
+		"dependentRequired": {"total": ["lines"]},
+		"unevaluatedProperties": false,
+		"$defs": {"sku": {"type": "string", "pattern": "^[A-Z]+$"}}
+	}`
+	compiler := NewCompiler()
+	before, err := compiler.Compile([]byte(source))
+	require.NoError(t, err)
+	downgraded, issues, err := before.Downgrade(DowngradeOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	after, err := compiler.Compile(downgraded)
+	require.NoError(t, err)
+	assert.Equal(t, Draft7, after.Dialect())
+
+	for _, instance := range []any{
+		map[string]any{"total": 1, "lines": []any{"ABC"}},
+		map[string]any{"total": 0, "lines": []any{"ABC"}},
+		map[string]any{"total": 1},
+		map[string]any{"lines": []any{"ABC", "DEF"}},
+		map[string]any{"lines": []any{"abc"}},
+		map[string]any{"other": true},
+	} {
+		assert.Equal(t, before.Validate(instance).IsValid(), after.Validate(instance).IsValid(), "%v", instance)
+	}
+}
+
+func TestSchemaDowngradeZeroMinContainsWithMaxContains(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"contains": {"const": 1}, "minContains": 0, "maxContains": 1}`))
+	require.NoError(t, err)
+
+	_, issues, err := schema.Downgrade(DowngradeOptions{})
+	require.ErrorIs(t, err, ErrLossyDowngrade)
+	assert.Equal(t, []MigrationIssue{
+		{Location: "", Keyword: "minContains", Message: "minContains has no Draft-07 equivalent and is dropped"},
+		{Location: "", Keyword: "maxContains", Message: "maxContains has no Draft-07 equivalent and is dropped"},
+	}, issues)
+
+	downgraded, _, err := schema.Downgrade(DowngradeOptions{Approximate: true})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"$schema": "http://json-schema.org/draft-07/schema#", "contains": {"const": 1}}`, string(downgraded))
+}
+
+func TestSchemaDowngradeSharedDependencies(t *testing.T) {
+	compiler := NewCompiler()
+	before, err := compiler.Compile([]byte(`{"dependentRequired": {"a": ["b"]}, "dependentSchemas": {"a": {"required": ["c"]}}}`))
+	require.NoError(t, err)
+	downgraded, _, err := before.Downgrade(DowngradeOptions{})
+	require.NoError(t, err)
+	after, err := compiler.Compile(downgraded)
+	require.NoError(t, err)
+
+	for _, instance := range []map[string]any{
+		{"a": 1, "b": 2, "c": 3},
+		{"a": 1, "c": 3},
+		{"a": 1, "b": 2},
+		{"b": 2},
+	} {
+		assert.Equal(t, before.Validate(instance).IsValid(), after.Validate(instance).IsValid(), "%v", instance)
+	}
+}
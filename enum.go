@@ -36,7 +36,7 @@ func evaluateEnum(schema *Schema, instance any) *EvaluationError {
 		allowed = append(allowed, fmt.Sprintf("%v", enumValue))
 	}
 
-	return NewEvaluationError("enum", "value_not_in_enum", "Value {received} should be one of the allowed values: {expected}", map[string]any{
+	return newError("enum", CodeValueNotInEnum, map[string]any{
 		"expected": strings.Join(allowed, ", "),
 		"received": instance,
 	})
@@ -0,0 +1,205 @@
+package jsonschema
+
+import "slices"
+
+// ErrorCode identifies a kind of validation failure. It is the value of
+// [EvaluationError.Code] and the message key translators look up.
+type ErrorCode string
+
+// Validation error codes reported in [EvaluationError.Code].
+const (
+	CodeAdditionalPropertiesMismatch  ErrorCode = "additional_properties_mismatch"
+	CodeAdditionalPropertyFalse       ErrorCode = "additional_property_false"
+	CodeAdditionalPropertyMismatch    ErrorCode = "additional_property_mismatch"
+	CodeAllOfItemMismatch             ErrorCode = "all_of_item_mismatch"
+	CodeAnyOfItemMismatch             ErrorCode = "any_of_item_mismatch"
+	CodeConstMismatch                 ErrorCode = "const_mismatch"
+	CodeConstMismatchNull             ErrorCode = "const_mismatch_null"
+	CodeContainsTooFewItems           ErrorCode = "contains_too_few_items"
+	CodeContainsTooManyItems          ErrorCode = "contains_too_many_items"
+	CodeContentSchemaMismatch         ErrorCode = "content_schema_mismatch"
+	CodeDependentPropertyRequired     ErrorCode = "dependent_property_required"
+	CodeDependentRequiredMissing      ErrorCode = "dependent_required_missing"
+	CodeDependentSchemaMismatch       ErrorCode = "dependent_schema_mismatch"
+	CodeDependentSchemasMismatch      ErrorCode = "dependent_schemas_mismatch"
+	CodeDynamicRefMismatch            ErrorCode = "dynamic_ref_mismatch"
+	CodeErrorMessage                  ErrorCode = "error_message"
+	CodeExclusiveMaximumMismatch      ErrorCode = "exclusive_maximum_mismatch"
+	CodeExclusiveMinimumMismatch      ErrorCode = "exclusive_minimum_mismatch"
+	CodeFalseSchemaMismatch           ErrorCode = "false_schema_mismatch"
+	CodeFormatMismatch                ErrorCode = "format_mismatch"
+	CodeIfElseMismatch                ErrorCode = "if_else_mismatch"
+	CodeIfThenMismatch                ErrorCode = "if_then_mismatch"
+	CodeInvalidEncoding               ErrorCode = "invalid_encoding"
+	CodeInvalidJSON                   ErrorCode = "invalid_json"
+	CodeInvalidMediaType              ErrorCode = "invalid_media_type"
+	CodeInvalidMultipleOf             ErrorCode = "invalid_multiple_of"
+	CodeInvalidNumeric                ErrorCode = "invalid_numeric"
+	CodeInvalidPattern                ErrorCode = "invalid_pattern"
+	CodeItemMismatch                  ErrorCode = "item_mismatch"
+	CodeItemsMismatch                 ErrorCode = "items_mismatch"
+	CodeItemsTooLong                  ErrorCode = "items_too_long"
+	CodeItemsTooShort                 ErrorCode = "items_too_short"
+	CodeMissingRequiredProperties     ErrorCode = "missing_required_properties"
+	CodeMissingRequiredProperty       ErrorCode = "missing_required_property"
+	CodeNotMultipleOf                 ErrorCode = "not_multiple_of"
+	CodeNotSchemaMismatch             ErrorCode = "not_schema_mismatch"
+	CodeOneOfItemMismatch             ErrorCode = "one_of_item_mismatch"
+	CodeOneOfMultipleMatches          ErrorCode = "one_of_multiple_matches"
+	CodePatternMismatch               ErrorCode = "pattern_mismatch"
+	CodePatternPropertiesMismatch     ErrorCode = "pattern_properties_mismatch"
+	CodePatternPropertyMismatch       ErrorCode = "pattern_property_mismatch"
+	CodePrefixItemMismatch            ErrorCode = "prefix_item_mismatch"
+	CodePrefixItemsMismatch           ErrorCode = "prefix_items_mismatch"
+	CodePropertiesMismatch            ErrorCode = "properties_mismatch"
+	CodePropertyMismatch              ErrorCode = "property_mismatch"
+	CodePropertyNameMismatch          ErrorCode = "property_name_mismatch"
+	CodePropertyNamesMismatch         ErrorCode = "property_names_mismatch"
+	CodeRefMismatch                   ErrorCode = "ref_mismatch"
+	CodeStringTooLong                 ErrorCode = "string_too_long"
+	CodeStringTooShort                ErrorCode = "string_too_short"
+	CodeTooFewProperties              ErrorCode = "too_few_properties"
+	CodeTooManyProperties             ErrorCode = "too_many_properties"
+	CodeTypeMismatch                  ErrorCode = "type_mismatch"
+	CodeUnevaluatedItemMismatch       ErrorCode = "unevaluated_item_mismatch"
+	CodeUnevaluatedItemsMismatch      ErrorCode = "unevaluated_items_mismatch"
+	CodeUnevaluatedItemsNotAllowed    ErrorCode = "unevaluated_items_not_allowed"
+	CodeUnevaluatedPropertiesMismatch ErrorCode = "unevaluated_properties_mismatch"
+	CodeUnevaluatedPropertyMismatch   ErrorCode = "unevaluated_property_mismatch"
+	CodeUniqueItemsMismatch           ErrorCode = "unique_items_mismatch"
+	CodeUnknownFormat                 ErrorCode = "unknown_format"
+	CodeUnsupportedEncoding           ErrorCode = "unsupported_encoding"
+	CodeUnsupportedMediaType          ErrorCode = "unsupported_media_type"
+	CodeValueAboveMaximum             ErrorCode = "value_above_maximum"
+	CodeValueBelowMinimum             ErrorCode = "value_below_minimum"
+	CodeValueNotInEnum                ErrorCode = "value_not_in_enum"
+)
+
+// Parameter types reported in [ErrorParam.Type].
+const (
+	ParamString  = "string"  // Go string
+	ParamInteger = "integer" // Go int
+	ParamNumber  = "number"  // Go float64
+	ParamAny     = "any"     // the offending instance value
+)
+
+// ErrorParam describes one placeholder available to an error template.
+type ErrorParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ErrorCodeInfo describes a validation error code: the keywords that report
+// it, its default English template, and the parameters passed to translators.
+//
+// Template is empty for [CodeErrorMessage], whose text comes from the schema's
+// errorMessage keyword rather than a catalog.
+type ErrorCodeInfo struct {
+	Code     ErrorCode    `json:"code"`
+	Keywords []string     `json:"keywords"`
+	Template string       `json:"template"`
+	Params   []ErrorParam `json:"params,omitempty"`
+}
+
+// ErrorCodes returns every validation error code the validator can report,
+// sorted by code. The returned slice is a copy and may be modified freely.
+func ErrorCodes() []ErrorCodeInfo {
+	infos := make([]ErrorCodeInfo, len(errorCodes))
+	for i, info := range errorCodes {
+		info.Keywords = slices.Clone(info.Keywords)
+		info.Params = slices.Clone(info.Params)
+		infos[i] = info
+	}
+	return infos
+}
+
+// newError reports code for keyword using the code's registered template.
+func newError(keyword string, code ErrorCode, params ...map[string]any) *EvaluationError {
+	return NewEvaluationError(keyword, string(code), errorCodeIndex[code].Template, params...)
+}
+
+// errorParams builds a parameter list from alternating names and types.
+func errorParams(names ...string) []ErrorParam {
+	result := make([]ErrorParam, 0, len(names)/2)
+	for i := 0; i+1 < len(names); i += 2 {
+		result = append(result, ErrorParam{Name: names[i], Type: names[i+1]})
+	}
+	return result
+}
+
+// errorCodes is the registry behind ErrorCodes, kept sorted by code.
+var errorCodes = []ErrorCodeInfo{
+	{CodeAdditionalPropertiesMismatch, []string{"additionalProperties"}, "Additional properties {properties} do not match the schema", errorParams("properties", ParamString)},
+	{CodeAdditionalPropertyFalse, []string{"additionalProperties"}, "Additional property '{property}' not allowed", errorParams("property", ParamString)},
+	{CodeAdditionalPropertyMismatch, []string{"additionalProperties"}, "Additional property {property} does not match the schema", errorParams("property", ParamString)},
+	{CodeAllOfItemMismatch, []string{"allOf"}, "Value does not match the allOf schema at index {indexs}", errorParams("indexs", ParamString)},
+	{CodeAnyOfItemMismatch, []string{"anyOf"}, "Value does not match anyOf schema", nil},
+	{CodeConstMismatch, []string{"const"}, "Value does not match the constant value", nil},
+	{CodeConstMismatchNull, []string{"const"}, "Value should be null", nil},
+	{CodeContainsTooFewItems, []string{"minContains"}, "Value should contain at least {min_contains} matching items", errorParams("min_contains", ParamInteger, "count", ParamInteger)},
+	{CodeContainsTooManyItems, []string{"maxContains"}, "Value should contain no more than {max_contains} matching items", errorParams("max_contains", ParamInteger, "count", ParamInteger)},
+	{CodeContentSchemaMismatch, []string{"contentSchema"}, "Content does not match the schema", nil},
+	{CodeDependentPropertyRequired, []string{"dependentRequired"}, "Some required property dependencies are missing: {missing_properties}", errorParams("missing_properties", ParamString)},
+	{CodeDependentRequiredMissing, []string{"dependentRequired"}, "Property {property} is required when {dependent_property} is present", errorParams("property", ParamString, "dependent_property", ParamString)},
+	{CodeDependentSchemaMismatch, []string{"dependentSchemas"}, "Property {property} does not match the dependent schema", errorParams("property", ParamString)},
+	{CodeDependentSchemasMismatch, []string{"dependentSchemas"}, "Properties {properties} do not match the dependent schemas", errorParams("properties", ParamString)},
+	{CodeDynamicRefMismatch, []string{"$dynamicRef"}, "Value does not match the dynamic reference schema", nil},
+	{CodeErrorMessage, []string{errorMessageKeyword}, "", nil},
+	{CodeExclusiveMaximumMismatch, []string{"exclusiveMaximum"}, "{value} should be less than {exclusive_maximum}", errorParams("value", ParamString, "exclusive_maximum", ParamString)},
+	{CodeExclusiveMinimumMismatch, []string{"exclusiveMinimum"}, "{value} should be greater than {exclusive_minimum}", errorParams("value", ParamString, "exclusive_minimum", ParamString)},
+	{CodeFalseSchemaMismatch, []string{"schema"}, "No values are allowed because the schema is set to 'false'", nil},
+	{CodeFormatMismatch, []string{"format"}, "Value does not match format '{format}'", errorParams("format", ParamString)},
+	{CodeIfElseMismatch, []string{"else"}, "Value fails the 'if' condition and does not match the 'else' schema", nil},
+	{CodeIfThenMismatch, []string{"then"}, "Value meets the 'if' condition but does not match the 'then' schema", nil},
+	{CodeInvalidEncoding, []string{"contentEncoding"}, "Error decoding data with '{encoding}'", errorParams("encoding", ParamString, "error", ParamString)},
+	{CodeInvalidJSON, []string{"format"}, "Invalid JSON format", nil},
+	{CodeInvalidMediaType, []string{"contentMediaType"}, "Error unmarshalling data with media type '{media_type}'", errorParams("media_type", ParamString, "error", ParamString)},
+	{CodeInvalidMultipleOf, []string{"multipleOf"}, "Multiple of {divisor} should be greater than 0", errorParams("divisor", ParamString)},
+	{CodeInvalidNumeric, []string{"type"}, "Value is {received} but should be numeric", errorParams("received", ParamString)},
+	{CodeInvalidPattern, []string{"pattern", "patternProperties"}, "Invalid regular expression pattern {pattern}", errorParams("pattern", ParamString)},
+	{CodeItemMismatch, []string{"items"}, "Item at index {index} does not match the schema", errorParams("index", ParamString)},
+	{CodeItemsMismatch, []string{"items"}, "Items at index {indexs} do not match the schema", errorParams("indexs", ParamString)},
+	{CodeItemsTooLong, []string{"maxItems"}, "Value should have at most {max_items} items", errorParams("max_items", ParamString, "count", ParamInteger)},
+	{CodeItemsTooShort, []string{"minItems"}, "Value should have at least {min_items} items", errorParams("min_items", ParamNumber, "count", ParamInteger)},
+	{CodeMissingRequiredProperties, []string{"required"}, "Required properties {properties} are missing", errorParams("properties", ParamString)},
+	{CodeMissingRequiredProperty, []string{"required"}, "Required property {property} is missing", errorParams("property", ParamString)},
+	{CodeNotMultipleOf, []string{"multipleOf"}, "{value} should be a multiple of {divisor}", errorParams("value", ParamString, "divisor", ParamString)},
+	{CodeNotSchemaMismatch, []string{"not"}, "Value should not match the not schema", nil},
+	{CodeOneOfItemMismatch, []string{"oneOf"}, "Value does not match the oneOf schema", nil},
+	{CodeOneOfMultipleMatches, []string{"oneOf"}, "Value should match exactly one schema but matches multiple at indexes {matches}", errorParams("matches", ParamString)},
+	{CodePatternMismatch, []string{"pattern"}, "Value does not match the required pattern {pattern}", errorParams("pattern", ParamString, "value", ParamAny)},
+	{CodePatternPropertiesMismatch, []string{"properties"}, "Properties {properties} do not match their pattern schemas", errorParams("properties", ParamString)},
+	{CodePatternPropertyMismatch, []string{"properties"}, "Property {property} does not match the pattern schema", errorParams("property", ParamString)},
+	{CodePrefixItemMismatch, []string{"prefixItems"}, "Item at index {index} does not match the prefixItems schema", errorParams("index", ParamString)},
+	{CodePrefixItemsMismatch, []string{"prefixItems"}, "Items at index {indexs} do not match the prefixItems schemas", errorParams("indexs", ParamString)},
+	{CodePropertiesMismatch, []string{"properties"}, "Properties {properties} do not match their schemas", errorParams("properties", ParamString)},
+	{CodePropertyMismatch, []string{"properties"}, "Property {property} does not match the schema", errorParams("property", ParamString)},
+	{CodePropertyNameMismatch, []string{"propertyNames"}, "Property name {property} does not match the schema", errorParams("property", ParamString)},
+	{CodePropertyNamesMismatch, []string{"propertyNames"}, "Property names {properties} do not match the schema", errorParams("properties", ParamString)},
+	{CodeRefMismatch, []string{"$ref"}, "Value does not match the reference schema", nil},
+	{CodeStringTooLong, []string{"maxLength"}, "Value should be at most {max_length} characters", errorParams("max_length", ParamString, "length", ParamInteger)},
+	{CodeStringTooShort, []string{"minLength"}, "Value should be at least {min_length} characters", errorParams("min_length", ParamNumber, "length", ParamInteger)},
+	{CodeTooFewProperties, []string{"minProperties"}, "Value should have at least {min_properties} properties", errorParams("min_properties", ParamNumber)},
+	{CodeTooManyProperties, []string{"maxProperties"}, "Value should have at most {max_properties} properties", errorParams("max_properties", ParamNumber)},
+	{CodeTypeMismatch, []string{"type"}, "Value is {received} but should be {expected}", errorParams("expected", ParamString, "received", ParamString)},
+	{CodeUnevaluatedItemMismatch, []string{"unevaluatedItems"}, "Item at index {index} does not match the unevaluatedItems schema", errorParams("index", ParamString)},
+	{CodeUnevaluatedItemsMismatch, []string{"unevaluatedItems"}, "Items at indexes {indexes} do not match the unevaluatedItems schema", errorParams("indexes", ParamString)},
+	{CodeUnevaluatedItemsNotAllowed, []string{"unevaluatedItems"}, "Unevaluated items are not allowed at indexes: {indexes}", errorParams("indexes", ParamString)},
+	{CodeUnevaluatedPropertiesMismatch, []string{"properties"}, "Properties {properties} do not match the unevaluatedProperties schema", errorParams("properties", ParamString)},
+	{CodeUnevaluatedPropertyMismatch, []string{"properties"}, "Property {property} does not match the unevaluatedProperties schema", errorParams("property", ParamString)},
+	{CodeUniqueItemsMismatch, []string{"uniqueItems"}, "Array items at indices {index1} and {index2} are not unique", errorParams("index1", ParamInteger, "index2", ParamInteger)},
+	{CodeUnknownFormat, []string{"format"}, "Unknown format '{format}'", errorParams("format", ParamString)},
+	{CodeUnsupportedEncoding, []string{"contentEncoding"}, "Encoding '{encoding}' is not supported", errorParams("encoding", ParamString)},
+	{CodeUnsupportedMediaType, []string{"contentMediaType"}, "Media type '{media_type}' is not supported", errorParams("media_type", ParamString)},
+	{CodeValueAboveMaximum, []string{"maximum"}, "{value} should be at most {maximum}", errorParams("value", ParamString, "maximum", ParamString)},
+	{CodeValueBelowMinimum, []string{"minimum"}, "{value} should be at least {minimum}", errorParams("value", ParamString, "minimum", ParamString)},
+	{CodeValueNotInEnum, []string{"enum"}, "Value {received} should be one of the allowed values: {expected}", errorParams("expected", ParamString, "received", ParamAny)},
+}
+
+var errorCodeIndex = func() map[ErrorCode]ErrorCodeInfo {
+	index := make(map[ErrorCode]ErrorCodeInfo, len(errorCodes))
+	for _, info := range errorCodes {
+		index[info.Code] = info
+	}
+	return index
+}()
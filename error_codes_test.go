@@ -0,0 +1,94 @@
+package jsonschema
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+func TestErrorCodesRegistry(t *testing.T) {
+	codes := ErrorCodes()
+	require.NotEmpty(t, codes)
+	assert.True(t, slices.IsSortedFunc(codes, func(a, b ErrorCodeInfo) int {
+		return cmp.Compare(a.Code, b.Code)
+	}), "codes are sorted")
+
+	seen := make(map[ErrorCode]bool, len(codes))
+	for _, info := range codes {
+		assert.False(t, seen[info.Code], "duplicate code %s", info.Code)
+		seen[info.Code] = true
+		assert.NotEmpty(t, info.Keywords, "code %s has keywords", info.Code)
+
+		names := make([]string, 0, len(info.Params))
+		for _, param := range info.Params {
+			assert.Contains(t, []string{ParamString, ParamInteger, ParamNumber, ParamAny}, param.Type,
+				"code %s param %s", info.Code, param.Name)
+			names = append(names, param.Name)
+		}
+		for _, match := range placeholderPattern.FindAllStringSubmatch(info.Template, -1) {
+			assert.Contains(t, names, match[1], "code %s template placeholder is a declared param", info.Code)
+		}
+	}
+}
+
+func TestErrorCodesReturnsCopy(t *testing.T) {
+	codes := ErrorCodes()
+	codes[0].Keywords[0] = "mutated"
+	assert.NotEqual(t, "mutated", ErrorCodes()[0].Keywords[0])
+}
+
+func TestReportedErrorsUseRegisteredCodes(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "maxLength": 3},
+			"tags": {"type": "array", "maxItems": 1, "uniqueItems": true},
+			"count": {"multipleOf": 2}
+		},
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]any{"name": "toolong", "tags": []any{"a", "a"}, "count": 3})
+	require.False(t, result.IsValid())
+
+	registered := make(map[string]ErrorCodeInfo)
+	for _, info := range ErrorCodes() {
+		registered[string(info.Code)] = info
+	}
+	goTypes := map[string]string{ParamString: "string", ParamInteger: "int", ParamNumber: "float64"}
+
+	var reported []string
+	var collect func(result *EvaluationResult)
+	collect = func(result *EvaluationResult) {
+		for _, evalErr := range result.Errors {
+			info, ok := registered[evalErr.Code]
+			assert.True(t, ok, "code %s is registered", evalErr.Code)
+			for _, param := range info.Params {
+				if goType, typed := goTypes[param.Type]; typed {
+					assert.Equal(t, goType, fmt.Sprintf("%T", evalErr.Params[param.Name]), "code %s param %s", evalErr.Code, param.Name)
+				}
+			}
+			reported = append(reported, evalErr.Code)
+		}
+		for _, detail := range result.Details {
+			collect(detail)
+		}
+	}
+	collect(result)
+
+	assert.Subset(t, reported, []string{
+		string(CodeMissingRequiredProperty),
+		string(CodeStringTooLong),
+		string(CodeItemsTooLong),
+		string(CodeUniqueItemsMismatch),
+		string(CodeNotMultipleOf),
+	})
+}
@@ -0,0 +1,498 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/kaptinlin/jsonpointer"
+)
+
+// errorMessageKeyword is the keyword and result key used for custom messages
+// that replace every error of a schema.
+const errorMessageKeyword = "errorMessage"
+
+// LocalizedMessage is a custom message template with optional per-locale
+// variants. In a schema it is written either as a plain string or as an
+// object keyed by locale, e.g. {"en": "Invalid postcode", "de-DE": "Ungültige
+// Postleitzahl"}. The empty key holds the locale-neutral template of the
+// string form.
+type LocalizedMessage map[string]string
+
+// String returns the template used when no locale is requested: the
+// locale-neutral template, then "en", then the first locale in sorted order.
+func (m LocalizedMessage) String() string {
+	if message, ok := m[""]; ok {
+		return message
+	}
+	if message, ok := m[defaultMessageLocale]; ok {
+		return message
+	}
+	for _, locale := range slices.Sorted(maps.Keys(m)) {
+		return m[locale]
+	}
+	return ""
+}
+
+// ForLocale returns the template for the given locale. It tries an exact
+// (case-insensitive) match first, then a variant sharing the same base
+// language, and finally falls back to String.
+func (m LocalizedMessage) ForLocale(locale string) string {
+	if locale == "" {
+		return m.String()
+	}
+	for key, message := range m {
+		if key != "" && strings.EqualFold(key, locale) {
+			return message
+		}
+	}
+	language := baseLanguage(locale)
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		if key != "" && strings.EqualFold(baseLanguage(key), language) {
+			return m[key]
+		}
+	}
+	return m.String()
+}
+
+const defaultMessageLocale = "en"
+
+// baseLanguage returns the primary language subtag of a BCP 47 locale tag.
+func baseLanguage(locale string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return language
+}
+
+// MarshalJSON writes the string form when the message has no locale variants.
+func (m LocalizedMessage) MarshalJSON() ([]byte, error) {
+	if message, ok := m[""]; ok && len(m) == 1 {
+		return json.Marshal(message)
+	}
+	return json.Marshal(map[string]string(m), json.Deterministic(true))
+}
+
+// UnmarshalJSON accepts either a string or an object of locale variants.
+func (m *LocalizedMessage) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*m = LocalizedMessage{"": message}
+		return nil
+	}
+
+	var variants map[string]string
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return ErrInvalidErrorMessage
+	}
+	*m = LocalizedMessage(variants)
+	return nil
+}
+
+// ErrorMessage holds the custom messages declared by the "errorMessage"
+// keyword, following the conventions of ajv-errors:
+//
+//   - a string (or the "_" member of an object) replaces every error reported
+//     for the schema and its subschemas that no more specific entry handled;
+//   - any other member names a keyword whose error it replaces;
+//   - "properties" and "items" replace all errors reported for a property
+//     name or array index;
+//   - "required", "dependentRequired" and "dependencies" accept an object
+//     keyed by property name to replace the error for individual properties.
+//
+// Templates reference error parameters as {param}, the instance value as
+// ${0} or ${0/relative/pointer}, and the root instance as ${/json/pointer}.
+//
+// Reference: https://github.com/ajv-validator/ajv-errors
+type ErrorMessage struct {
+	Message      LocalizedMessage            // Message for every error not handled by a more specific entry.
+	Keywords     map[string]LocalizedMessage // Messages replacing the error of a single keyword.
+	Properties   map[string]LocalizedMessage // Messages replacing all errors reported for a property.
+	Items        []LocalizedMessage          // Messages replacing all errors reported for an array index.
+	Required     map[string]LocalizedMessage // Messages for individual missing required properties.
+	Dependencies map[string]LocalizedMessage // Messages for individual dependentRequired trigger properties.
+}
+
+// MarshalJSON writes the string form when only a schema-wide message is set.
+func (em *ErrorMessage) MarshalJSON() ([]byte, error) {
+	if len(em.Keywords) == 0 && len(em.Properties) == 0 && len(em.Items) == 0 &&
+		len(em.Required) == 0 && len(em.Dependencies) == 0 && em.Message != nil {
+		return em.Message.MarshalJSON()
+	}
+
+	members := make(map[string]any, len(em.Keywords)+5)
+	for keyword, message := range em.Keywords {
+		members[keyword] = message
+	}
+	if em.Message != nil {
+		members["_"] = em.Message
+	}
+	if len(em.Properties) > 0 {
+		members["properties"] = em.Properties
+	}
+	if len(em.Items) > 0 {
+		members["items"] = em.Items
+	}
+	if len(em.Required) > 0 {
+		members["required"] = em.Required
+	}
+	if len(em.Dependencies) > 0 {
+		members["dependentRequired"] = em.Dependencies
+	}
+	return json.Marshal(members, json.Deterministic(true))
+}
+
+// UnmarshalJSON parses the string and object forms of "errorMessage".
+func (em *ErrorMessage) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '"' {
+		var message LocalizedMessage
+		if err := message.UnmarshalJSON(trimmed); err != nil {
+			return err
+		}
+		*em = ErrorMessage{Message: message}
+		return nil
+	}
+
+	var members map[string]jsontext.Value
+	if err := json.Unmarshal(data, &members); err != nil {
+		return ErrInvalidErrorMessage
+	}
+
+	parsed := ErrorMessage{}
+	for name, raw := range members {
+		var err error
+		switch name {
+		case "_":
+			err = json.Unmarshal(raw, &parsed.Message)
+		case "properties":
+			err = json.Unmarshal(raw, &parsed.Properties)
+		case "items":
+			err = json.Unmarshal(raw, &parsed.Items)
+		case "required", "dependentRequired", "dependencies":
+			if raw.Kind() == '{' {
+				target := &parsed.Dependencies
+				if name == "required" {
+					target = &parsed.Required
+				}
+				err = json.Unmarshal(raw, target)
+				break
+			}
+			fallthrough
+		default:
+			var message LocalizedMessage
+			err = json.Unmarshal(raw, &message)
+			if parsed.Keywords == nil {
+				parsed.Keywords = make(map[string]LocalizedMessage)
+			}
+			parsed.Keywords[name] = message
+		}
+		if err != nil {
+			return ErrInvalidErrorMessage
+		}
+	}
+
+	*em = parsed
+	return nil
+}
+
+// applyErrorMessage rewrites the errors of a failed evaluation according to
+// the schema's "errorMessage" keyword.
+func (s *Schema) applyErrorMessage(result *EvaluationResult, instance any, dynamicScope *DynamicScope) {
+	em := s.ErrorMessage
+	if em == nil || result.IsValid() {
+		return
+	}
+
+	render := func(message LocalizedMessage, params map[string]any) LocalizedMessage {
+		return interpolateMessage(message, params, instance, dynamicScope.rootInstance())
+	}
+
+	for keyword, err := range result.Errors {
+		message, ok := em.Keywords[keyword]
+		switch {
+		case keyword == "required" && len(em.Required) > 0:
+			message, ok = joinedPropertyMessages(em.Required, missingRequired(s, instance))
+		case keyword == "dependentRequired" && len(em.Dependencies) > 0:
+			message, ok = joinedPropertyMessages(em.Dependencies, unmetDependencies(s, instance))
+		}
+		if ok {
+			result.Errors[keyword] = err.withMessages(render(message, err.Params))
+		}
+	}
+
+	covered := make(map[*EvaluationResult]bool)
+	for _, detail := range result.Details {
+		if detail.IsValid() {
+			continue
+		}
+		message, ok := em.detailMessage(detail.InstanceLocation)
+		if !ok {
+			continue
+		}
+		detailInstance, _ := jsonpointer.Value(instance, escapeInstanceLocation(detail.InstanceLocation))
+		detail.clearErrors()
+		detail.AddError(newError(errorMessageKeyword, CodeErrorMessage).
+			withMessages(interpolateMessage(message, nil, detailInstance, dynamicScope.rootInstance())))
+		covered[detail] = true
+	}
+	if len(covered) > 0 {
+		dropCoveredApplicatorErrors(result, covered)
+	}
+
+	if em.Message == nil || !hasUncoveredErrors(result, covered) {
+		return
+	}
+
+	for keyword, err := range result.Errors {
+		if err.Messages == nil {
+			delete(result.Errors, keyword)
+		}
+	}
+	for _, detail := range result.Details {
+		if !covered[detail] {
+			detail.clearErrors()
+		}
+	}
+	result.AddError(newError(errorMessageKeyword, CodeErrorMessage).withMessages(render(em.Message, nil)))
+}
+
+// detailMessage returns the "properties" or "items" message for a direct
+// child result at the given relative instance location.
+func (em *ErrorMessage) detailMessage(instanceLocation string) (LocalizedMessage, bool) {
+	token, ok := strings.CutPrefix(instanceLocation, "/")
+	if !ok || token == "" {
+		return nil, false
+	}
+	if message, ok := em.Properties[token]; ok {
+		return message, true
+	}
+	if index, err := strconv.Atoi(token); err == nil && index >= 0 && index < len(em.Items) {
+		return em.Items[index], em.Items[index] != nil
+	}
+	return nil, false
+}
+
+// withMessages returns a copy of the error carrying the custom message.
+// Keyword, code and parameters are kept so the error stays identifiable.
+func (e *EvaluationError) withMessages(message LocalizedMessage) *EvaluationError {
+	custom := *e
+	custom.Message = message.String()
+	custom.Messages = message
+	return &custom
+}
+
+// applicatorKeywords lists the keywords whose errors only summarize failed
+// child results addressed by a property name or array index.
+var applicatorKeywords = []string{"properties", "patternProperties", "additionalProperties", "items", "prefixItems"}
+
+// dropCoveredApplicatorErrors removes summary errors whose failed child
+// results were all replaced by "properties" or "items" messages.
+func dropCoveredApplicatorErrors(result *EvaluationResult, covered map[*EvaluationResult]bool) {
+	for _, keyword := range applicatorKeywords {
+		err, exists := result.Errors[keyword]
+		if !exists || err.Messages != nil {
+			continue
+		}
+		allCovered := true
+		for _, detail := range result.Details {
+			if !detail.IsValid() && strings.HasPrefix(detail.EvaluationPath, "/"+keyword+"/") && !covered[detail] {
+				allCovered = false
+				break
+			}
+		}
+		if allCovered {
+			delete(result.Errors, keyword)
+		}
+	}
+}
+
+// hasUncoveredErrors reports whether any error of result or its subtree has
+// not been replaced by a more specific custom message.
+func hasUncoveredErrors(result *EvaluationResult, covered map[*EvaluationResult]bool) bool {
+	for _, err := range result.Errors {
+		if err.Messages == nil {
+			return true
+		}
+	}
+	for _, detail := range result.Details {
+		if !covered[detail] && detail.hasErrors() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasErrors reports whether the result or any of its descendants holds an error.
+func (e *EvaluationResult) hasErrors() bool {
+	if len(e.Errors) > 0 {
+		return true
+	}
+	for _, detail := range e.Details {
+		if detail.hasErrors() {
+			return true
+		}
+	}
+	return false
+}
+
+// clearErrors removes the errors of the result and all of its descendants.
+// Validity flags are kept so callers still see where validation failed.
+func (e *EvaluationResult) clearErrors() {
+	e.Errors = nil
+	for _, detail := range e.Details {
+		detail.clearErrors()
+	}
+}
+
+// joinedPropertyMessages joins the per-property messages for the given
+// properties. It reports false unless every property has a message.
+func joinedPropertyMessages(messages map[string]LocalizedMessage, properties []string) (LocalizedMessage, bool) {
+	if len(properties) == 0 {
+		return nil, false
+	}
+
+	locales := make(map[string]bool)
+	for _, property := range properties {
+		message, ok := messages[property]
+		if !ok {
+			return nil, false
+		}
+		for locale := range message {
+			locales[locale] = true
+		}
+	}
+
+	joined := make(LocalizedMessage, len(locales))
+	for locale := range locales {
+		parts := make([]string, len(properties))
+		for i, property := range properties {
+			parts[i] = messages[property].ForLocale(locale)
+		}
+		joined[locale] = strings.Join(parts, "; ")
+	}
+	return joined, true
+}
+
+// missingRequired lists the required properties absent from the instance.
+func missingRequired(schema *Schema, instance any) []string {
+	object, ok := instance.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var missing []string
+	for _, property := range schema.Required {
+		if _, exists := object[property]; !exists {
+			missing = append(missing, property)
+		}
+	}
+	return missing
+}
+
+// unmetDependencies lists, in sorted order, the dependentRequired trigger
+// properties whose dependencies are missing from the instance.
+func unmetDependencies(schema *Schema, instance any) []string {
+	object, ok := instance.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var unmet []string
+	for property, dependencies := range schema.DependentRequired {
+		if _, exists := object[property]; !exists {
+			continue
+		}
+		for _, dependency := range dependencies {
+			if _, exists := object[dependency]; !exists {
+				unmet = append(unmet, property)
+				break
+			}
+		}
+	}
+	slices.Sort(unmet)
+	return unmet
+}
+
+// interpolateMessage renders the {param} placeholders and ${...} instance
+// references of every variant.
+func interpolateMessage(message LocalizedMessage, params map[string]any, instance, root any) LocalizedMessage {
+	rendered := make(LocalizedMessage, len(message))
+	for locale, template := range message {
+		rendered[locale] = interpolateInstance(template, params, instance, root)
+	}
+	return rendered
+}
+
+// interpolateInstance replaces {param} with a parameter value, ${/pointer}
+// with the value at a JSON Pointer into the root instance, and ${0} or
+// ${0/pointer} with the current instance or a value below it. Unresolvable
+// references render as empty strings; unknown placeholders are kept. The
+// template is scanned once, so substituted text is never expanded again.
+func interpolateInstance(template string, params map[string]any, instance, root any) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		name := template[start+1 : end]
+		value, isParam := params[name]
+		switch {
+		case start > 0 && template[start-1] == '$':
+			sb.WriteString(template[:start-1])
+			sb.WriteString(instanceReference(name, instance, root))
+		case isParam:
+			sb.WriteString(template[:start])
+			sb.WriteString(fmt.Sprint(value))
+		default:
+			sb.WriteString(template[:start+1])
+			template = template[start+1:]
+			continue
+		}
+		template = template[end+1:]
+	}
+	sb.WriteString(template)
+	return sb.String()
+}
+
+func instanceReference(reference string, instance, root any) string {
+	document := root
+	if relative, ok := strings.CutPrefix(reference, "0"); ok {
+		document = instance
+		reference = relative
+	}
+	if reference != "" && !strings.HasPrefix(reference, "/") {
+		return ""
+	}
+
+	value, err := jsonpointer.Value(document, reference)
+	if err != nil {
+		return ""
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	encoded, err := marshalJSON(value)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+// escapeInstanceLocation turns a relative instance location, whose tokens are
+// written unescaped by the evaluators, into a JSON Pointer.
+func escapeInstanceLocation(location string) string {
+	token, _ := strings.CutPrefix(location, "/")
+	return "/" + jsonpointer.EscapeToken(token)
+}
@@ -0,0 +1,140 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// localeTranslator is a fakeTranslator bound to a locale.
+type localeTranslator struct {
+	fakeTranslator
+	locale string
+}
+
+func (l localeTranslator) Locale() string { return l.locale }
+
+func TestErrorMessageStringReplacesAllErrors(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {"postcode": {"type": "string", "pattern": "^[0-9]{5}$"}},
+		"errorMessage": "Please enter a valid address"
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]any{"postcode": "abc"})
+	require.False(t, result.IsValid())
+	assert.Equal(t, map[string]string{"errorMessage": "Please enter a valid address"}, result.DetailedErrors())
+}
+
+func TestErrorMessageKeywordTemplates(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "string",
+		"minLength": 5,
+		"errorMessage": {
+			"type": "Expected text",
+			"minLength": "'${0}' needs at least {min_length} characters"
+		}
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate("abc")
+	require.False(t, result.IsValid())
+	minLength := result.Errors["minLength"]
+	require.NotNil(t, minLength)
+	assert.Equal(t, "string_too_short", minLength.Code, "code is kept for programmatic handling")
+	assert.Equal(t, "'abc' needs at least 5 characters", minLength.Error())
+
+	result = schema.Validate(42)
+	assert.Equal(t, "Expected text", result.Errors["type"].Error())
+
+	schema, err = NewCompiler().Compile([]byte(`{
+		"maxLength": 3,
+		"errorMessage": {"maxLength": "'${0}' has more than {max_length} characters"}
+	}`))
+	require.NoError(t, err)
+	maxLength := schema.Validate("{max_length}").Errors["maxLength"]
+	require.NotNil(t, maxLength)
+	assert.Equal(t, "'{max_length}' has more than 3 characters", maxLength.Error(), "placeholders in instance values are not expanded")
+	assert.Equal(t, "'{max_length}' has more than 3 characters", maxLength.Localize(nil))
+}
+
+func TestErrorMessagePropertiesAndFallback(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"postcode": {"type": "string", "pattern": "^[0-9]{5}$"},
+			"age": {"type": "integer", "minimum": 0}
+		},
+		"required": ["postcode", "country"],
+		"errorMessage": {
+			"properties": {"postcode": "Please enter a valid postcode, not ${/postcode}"},
+			"required": {"country": "Country is required"},
+			"_": "Invalid address"
+		}
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]any{"postcode": "abc", "country": "DE"})
+	assert.Equal(t, map[string]string{
+		"/postcode/errorMessage": "Please enter a valid postcode, not abc",
+	}, result.DetailedErrors())
+
+	result = schema.Validate(map[string]any{"postcode": "12345"})
+	assert.Equal(t, map[string]string{"required": "Country is required"}, result.DetailedErrors())
+
+	result = schema.Validate(map[string]any{"postcode": "12345", "country": "DE", "age": -1})
+	assert.Equal(t, map[string]string{"errorMessage": "Invalid address"}, result.DetailedErrors())
+}
+
+func TestErrorMessageItems(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "array",
+		"items": {"type": "integer"},
+		"errorMessage": {"items": ["First item must be an integer"]}
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate([]any{"a"})
+	assert.Equal(t, map[string]string{"/0/errorMessage": "First item must be an integer"}, result.DetailedErrors())
+}
+
+func TestErrorMessageLocaleVariants(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "string",
+		"pattern": "^[0-9]{5}$",
+		"errorMessage": {"pattern": {"en": "Please enter a valid postcode", "de-DE": "Bitte eine gültige Postleitzahl eingeben"}}
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate("abc")
+	patternErr := result.Errors["pattern"]
+	require.NotNil(t, patternErr)
+
+	assert.Equal(t, "Please enter a valid postcode", patternErr.Error())
+	assert.Equal(t, "Please enter a valid postcode", patternErr.Localize(nil))
+	assert.Equal(t, "Bitte eine gültige Postleitzahl eingeben", patternErr.Localize(localeTranslator{locale: "de-DE"}))
+	assert.Equal(t, "Bitte eine gültige Postleitzahl eingeben", patternErr.Localize(localeTranslator{locale: "de-AT"}))
+	assert.Equal(t, "Please enter a valid postcode", patternErr.Localize(localeTranslator{locale: "fr-FR"}))
+	assert.Equal(t, "Please enter a valid postcode",
+		patternErr.Localize(fakeTranslator{"pattern_mismatch": "translated"}),
+		"custom messages take precedence over code-based translations")
+}
+
+func TestErrorMessageRoundTrip(t *testing.T) {
+	input := `{"errorMessage":{"_":"Invalid","minLength":{"de":"Zu kurz","en":"Too short"},"properties":{"a":"Bad a"},"required":{"a":"Need a"}},"minLength":2}`
+	schema, err := NewCompiler().Compile([]byte(input))
+	require.NoError(t, err)
+	assert.Nil(t, schema.Extra, "errorMessage is a recognized keyword")
+
+	output, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, input, string(output))
+}
+
+func TestErrorMessageRejectsInvalidValue(t *testing.T) {
+	_, err := NewCompiler().Compile([]byte(`{"errorMessage": 42}`))
+	require.ErrorIs(t, err, ErrInvalidErrorMessage)
+}
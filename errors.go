@@ -19,6 +19,23 @@ var (
 	// ErrInvalidStatusCode reports an invalid HTTP status code.
 	ErrInvalidStatusCode = errors.New("invalid http status code")
 
+	// ErrNetworkDisabled reports a remote fetch attempted while the network
+	// is disabled. It wraps ErrReferenceResolution.
+	ErrNetworkDisabled = fmt.Errorf("network disabled: %w", ErrReferenceResolution)
+
+	// ErrURLNotAllowed reports a remote URL outside the loader's allowlists.
+	ErrURLNotAllowed = errors.New("url not allowed")
+
+	// ErrBlockedAddress reports a connection to a loopback, private or
+	// link-local address refused by the loader.
+	ErrBlockedAddress = errors.New("address blocked")
+
+	// ErrResponseTooLarge reports a remote schema exceeding the size limit.
+	ErrResponseTooLarge = errors.New("response too large")
+
+	// ErrTooManyRedirects reports a remote fetch exceeding the redirect limit.
+	ErrTooManyRedirects = errors.New("too many redirects")
+
 	// ErrFileWrite reports a file write failure.
 	ErrFileWrite = errors.New("file write failed")
 
@@ -69,6 +86,12 @@ var (
 	// ErrYAMLUnmarshal reports a YAML unmarshal failure.
 	ErrYAMLUnmarshal = errors.New("yaml unmarshal failed")
 
+	// ErrUnsupportedYAML reports a YAML feature that has no JSON equivalent.
+	ErrUnsupportedYAML = fmt.Errorf("unsupported yaml: %w", ErrYAMLUnmarshal)
+
+	// ErrStrictMode reports a schema rejected by strict mode, see StrictError.
+	ErrStrictMode = errors.New("strict mode violation")
+
 	// ErrJSONDecode reports a JSON decode failure.
 	ErrJSONDecode = errors.New("json decode failed")
 
@@ -89,6 +112,34 @@ var (
 	// ErrSchemaCompilation reports a schema compilation failure.
 	ErrSchemaCompilation = errors.New("schema compilation failed")
 
+	// ErrUnresolvedReference reports a "$ref" whose target cannot be found.
+	ErrUnresolvedReference = errors.New("unresolved reference")
+
+	// ErrCircularReference reports a chain of references leading back to
+	// its start.
+	ErrCircularReference = errors.New("circular reference")
+
+	// ErrUnsupportedMigration reports a schema Migrate cannot rewrite for the
+	// requested dialect.
+	ErrUnsupportedMigration = errors.New("unsupported migration")
+
+	// ErrLossyDowngrade reports a schema Downgrade cannot express exactly in
+	// Draft-07.
+	ErrLossyDowngrade = errors.New("lossy downgrade")
+
+	// ErrDuplicateSchemaID reports two schemas declaring the same "$id".
+	ErrDuplicateSchemaID = errors.New("duplicate schema id")
+
+	// ErrLockfileMismatch reports a fetched schema whose content differs from
+	// its lockfile pin.
+	ErrLockfileMismatch = errors.New("schema does not match lockfile")
+
+	// ErrSchemaNotPinned reports a fetched schema missing from the lockfile.
+	ErrSchemaNotPinned = errors.New("schema not pinned in lockfile")
+
+	// ErrUnsupportedLockfileVersion reports a lockfile of an unknown version.
+	ErrUnsupportedLockfileVersion = errors.New("unsupported lockfile version")
+
 	// ErrReferenceResolution reports a reference resolution failure.
 	ErrReferenceResolution = errors.New("reference resolution failed")
 
@@ -118,6 +169,9 @@ var (
 
 	// ErrRegexValidation reports an invalid regular expression in a schema.
 	ErrRegexValidation = errors.New("regex validation failed")
+
+	// ErrInvalidErrorMessage reports a malformed "errorMessage" keyword.
+	ErrInvalidErrorMessage = errors.New("invalid errorMessage keyword")
 )
 
 // RegexPatternError provides structured context for invalid regular expressions discovered during schema compilation.
@@ -5,6 +5,7 @@ package jsonschema
 //   - The "format" keyword defines the data format expected for a value.
 //   - The format must be a string that names a specific format which the value should conform to.
 //   - The function uses custom formats first, then falls back to the global `Formats` map.
+//   - In the OpenAPI30 dialect, the numeric formats int32, int64, float, and double are always asserted.
 //   - If the format is not supported or not found, it may fall back to a no-op validation depending on configuration.
 //
 // This method ensures that data matches the expected format as specified in the schema.
@@ -25,9 +26,7 @@ func evaluateFormat(schema *Schema, value any) *EvaluationError {
 
 	// 1. Check compiler-specific custom formats first
 	if compiler != nil {
-		compiler.customFormatsRW.RLock()
-		formatDef = compiler.customFormats[formatName]
-		compiler.customFormatsRW.RUnlock()
+		formatDef = compiler.customFormat(formatName)
 	}
 
 	if formatDef != nil {
@@ -39,16 +38,24 @@ func evaluateFormat(schema *Schema, value any) *EvaluationError {
 			}
 		}
 		customValidator = formatDef.Validate
+	} else if schema.Dialect() == OpenAPI30 && openAPI30NumericFormats[formatName] != nil {
+		// OpenAPI 3.0 numeric formats are part of the data type
+		if !openAPI30NumericFormats[formatName](value) {
+			return newError("format", CodeFormatMismatch, map[string]any{"format": formatName})
+		}
+		return nil
 	} else if globalValidator, ok := Formats[formatName]; ok {
 		// Fallback to global formats
 		customValidator = globalValidator
+	} else if openAPIValidator, ok := openAPI30Formats[formatName]; ok && schema.Dialect() == OpenAPI30 {
+		customValidator = openAPIValidator
 	}
 
 	// If a validator was found (either custom or global)
 	if customValidator != nil {
 		if !customValidator(value) {
 			if compiler != nil && compiler.AssertFormat {
-				return NewEvaluationError("format", "format_mismatch", "Value does not match format '{format}'", map[string]any{"format": formatName})
+				return newError("format", CodeFormatMismatch, map[string]any{"format": formatName})
 			}
 		}
 		return nil // Validation passed or not asserted
@@ -56,7 +63,7 @@ func evaluateFormat(schema *Schema, value any) *EvaluationError {
 
 	// If no validator was found and AssertFormat is true, fail
 	if compiler != nil && compiler.AssertFormat {
-		return NewEvaluationError("format", "unknown_format", "Unknown format '{format}'", map[string]any{"format": formatName})
+		return newError("format", CodeUnknownFormat, map[string]any{"format": formatName})
 	}
 
 	return nil // Default behavior: ignore unknown formats
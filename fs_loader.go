@@ -0,0 +1,115 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// fsMount maps schema URIs starting with prefix onto files in fsys.
+type fsMount struct {
+	prefix string
+	fsys   fs.FS
+}
+
+// RegisterFS serves schemas whose URI starts with baseURI from fsys, so
+// "$ref"s under that prefix resolve without a network or file loader. The
+// rest of the URI after baseURI is the file path within fsys:
+//
+//	//go:embed schemas
+//	var schemasFS embed.FS
+//
+//	sub, _ := fs.Sub(schemasFS, "schemas")
+//	compiler.RegisterFS("https://schemas.example.com/", sub)
+//
+// Relative references between the served files resolve against their URIs
+// and are therefore served from fsys as well. When several prefixes match,
+// the longest one wins. Mounts take precedence over scheme loaders.
+func (c *Compiler) RegisterFS(baseURI string, fsys fs.FS) *Compiler {
+	if !strings.HasSuffix(baseURI, "/") {
+		baseURI += "/"
+	}
+	c.mu.Lock()
+	c.fsMounts = append(c.fsMounts, fsMount{prefix: baseURI, fsys: fsys})
+	c.mu.Unlock()
+	return c
+}
+
+// openMounted opens the file serving uri from the longest matching mount.
+// It reports false when no mount matches.
+func (c *Compiler) openMounted(uri string) (io.ReadCloser, bool, error) {
+	var mount fsMount
+	for compiler := c; compiler != nil; compiler = compiler.parent {
+		compiler.mu.RLock()
+		for _, candidate := range compiler.fsMounts {
+			if strings.HasPrefix(uri, candidate.prefix) && len(candidate.prefix) > len(mount.prefix) {
+				mount = candidate
+			}
+		}
+		compiler.mu.RUnlock()
+	}
+	if mount.fsys == nil {
+		return nil, false, nil
+	}
+
+	rest, _, _ := strings.Cut(strings.TrimPrefix(uri, mount.prefix), "?")
+	name, err := url.PathUnescape(rest)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %w", ErrDataRead, err)
+	}
+	file, err := mount.fsys.Open(name)
+	if err != nil {
+		return nil, true, err
+	}
+	return file, true, nil
+}
+
+// FileLoader returns a loader for "file" URLs. With an empty root it opens
+// the path of the URL as is. Otherwise paths are resolved inside root and
+// cannot escape it, so "file:///user.json" opens root/user.json.
+//
+// No loader is registered for the "file" scheme by default, so a schema
+// cannot read local files through "$ref" unless the caller opts in:
+//
+//	compiler.RegisterLoader("file", jsonschema.FileLoader("./schemas"))
+//
+// Prefer a root when schemas come from untrusted sources.
+func FileLoader(root string) func(url string) (io.ReadCloser, error) {
+	return func(rawURL string) (io.ReadCloser, error) {
+		name, err := fileURLPath(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		if root == "" {
+			return os.Open(name)
+		}
+
+		dir, err := os.OpenRoot(root)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = dir.Close() }()
+		return dir.Open(strings.TrimLeft(name, `/\`))
+	}
+}
+
+// fileURLPath returns the local path named by a "file" URL.
+func fileURLPath(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidFilenamePath, err)
+	}
+	if parsed.Scheme != "file" || (parsed.Host != "" && parsed.Host != "localhost") {
+		return "", fmt.Errorf("%w: %s", ErrInvalidFilenamePath, rawURL)
+	}
+	name := parsed.Path
+	if runtime.GOOS == "windows" && len(name) > 2 && name[0] == '/' && name[2] == ':' {
+		name = name[1:] // file:///C:/schemas/user.json
+	}
+	return filepath.FromSlash(name), nil
+}
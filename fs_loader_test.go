@@ -0,0 +1,82 @@
+package jsonschema
+
+import (
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFSResolvesRelativeRefs(t *testing.T) {
+	schemas := fstest.MapFS{
+		"user.json":           {Data: []byte(`{"type": "object", "properties": {"address": {"$ref": "common/address.json"}}}`)},
+		"common/address.json": {Data: []byte(`{"type": "object", "properties": {"zip": {"$ref": "zip.json#/$defs/zip"}}}`)},
+		"common/zip.json":     {Data: []byte(`{"$defs": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}}`)},
+	}
+	compiler := NewCompiler().RegisterFS("https://schemas.example.com", schemas)
+	delete(compiler.Loaders, "https") // prove nothing goes to the network
+
+	schema, err := compiler.Schema("https://schemas.example.com/user.json")
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate(map[string]any{"address": map[string]any{"zip": "12345"}}).IsValid())
+	result := schema.Validate(map[string]any{"address": map[string]any{"zip": "abc"}})
+	assert.False(t, result.IsValid())
+	assert.Len(t, result.ErrorsAt("/address/zip"), 1)
+}
+
+func TestRegisterFSLongestPrefixWins(t *testing.T) {
+	compiler := NewCompiler().
+		RegisterFS("https://schemas.example.com/", fstest.MapFS{"v2/item.json": {Data: []byte(`{"type": "integer"}`)}}).
+		RegisterFS("https://schemas.example.com/v2/", fstest.MapFS{"item.json": {Data: []byte(`{"type": "string"}`)}})
+
+	schema, err := compiler.Schema("https://schemas.example.com/v2/item.json")
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("text").IsValid())
+}
+
+func TestRegisterFSMissingFile(t *testing.T) {
+	compiler := NewCompiler().RegisterFS("https://schemas.example.com/", fstest.MapFS{})
+
+	_, err := compiler.Schema("https://schemas.example.com/missing.json")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func writeSchemaFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "defs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "root.json"), []byte(`{"$ref": "defs/name.json"}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "defs", "name.json"), []byte(`{"type": "string", "minLength": 2}`), 0o600))
+	return dir
+}
+
+func TestFileLoaderResolvesRelativeRefs(t *testing.T) {
+	dir := writeSchemaFiles(t)
+	rootURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, "root.json"))}).String()
+
+	_, err := NewCompiler().Schema(rootURL)
+	require.ErrorIs(t, err, ErrNoLoaderRegistered, "file URLs are not loaded by default")
+
+	schema, err := NewCompiler().RegisterLoader("file", FileLoader("")).Schema(rootURL)
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("Al").IsValid())
+	assert.False(t, schema.Validate("A").IsValid())
+}
+
+func TestFileLoaderWithRoot(t *testing.T) {
+	dir := writeSchemaFiles(t)
+	compiler := NewCompiler().RegisterLoader("file", FileLoader(dir))
+
+	schema, err := compiler.Schema("file:///root.json")
+	require.NoError(t, err)
+	assert.False(t, schema.Validate("A").IsValid())
+
+	_, err = compiler.Schema("file:///../outside.json")
+	require.Error(t, err, "paths cannot escape the root")
+}
@@ -0,0 +1,376 @@
+package jsonschema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"weak"
+)
+
+// DependencyGraph describes how schema resources reference each other. A
+// resource is a document or a subschema with its own "$id". References
+// within a resource are not edges.
+type DependencyGraph struct {
+	Nodes []DependencyNode // Sorted by URI.
+	Edges []DependencyEdge // Sorted by From, then Location.
+}
+
+// DependencyNode is a schema resource of a DependencyGraph.
+type DependencyNode struct {
+	URI    string  // "anonymous:N" for the Nth document without a URI.
+	Schema *Schema // Nil when a reference to URI could not be resolved.
+}
+
+// DependencyEdge is a reference from one schema resource to another.
+type DependencyEdge struct {
+	From     string // URI of the referencing resource.
+	Location string // JSON Pointer to the referencing schema within From.
+	Keyword  string // "$ref" or "$dynamicRef".
+	Ref      string // The reference as written.
+	To       string // URI of the referenced resource.
+}
+
+// DependencyGraph returns the resources of the document s belongs to and of
+// every resource its references lead to, directly or not. Unresolved
+// references lead to nodes without a schema.
+func (s *Schema) DependencyGraph() *DependencyGraph {
+	builder := newGraphBuilder()
+	builder.visit(s.rootSchema())
+	return builder.graph()
+}
+
+// DependencyGraph returns the graph of the schemas compiled by c and of the
+// resources their references lead to, see Schema.DependencyGraph. Documents
+// compiled without a URI are included while they are in use, numbered in
+// the order they were compiled.
+func (c *Compiler) DependencyGraph() *DependencyGraph {
+	c.mu.RLock()
+	var anonymous []*Schema
+	for _, pointer := range c.anonymous {
+		if schema := pointer.Value(); schema != nil {
+			anonymous = append(anonymous, schema)
+		}
+	}
+	schemas := slices.Collect(maps.Values(c.schemas))
+	c.mu.RUnlock()
+
+	builder := newGraphBuilder()
+	for _, schema := range anonymous {
+		builder.visit(schema)
+	}
+	for _, schema := range schemas {
+		builder.visit(schema.rootSchema())
+	}
+	return builder.graph()
+}
+
+// trackAnonymousLocked records a document compiled without a cacheable URI
+// for DependencyGraph. The pointers are weak so the compiler does not keep
+// such documents alive; collected ones are dropped when the slice is full.
+func (c *Compiler) trackAnonymousLocked(schema *Schema) {
+	if len(c.anonymous) == cap(c.anonymous) {
+		c.anonymous = slices.DeleteFunc(c.anonymous, func(pointer weak.Pointer[Schema]) bool {
+			return pointer.Value() == nil
+		})
+	}
+	c.anonymous = append(c.anonymous, weak.Make(schema))
+}
+
+// Dependencies returns the URIs of the resources uri references directly.
+func (g *DependencyGraph) Dependencies(uri string) []string {
+	var uris []string
+	for _, edge := range g.Edges {
+		if edge.From == uri && !slices.Contains(uris, edge.To) {
+			uris = append(uris, edge.To)
+		}
+	}
+	slices.Sort(uris)
+	return uris
+}
+
+// Dependents returns the URIs of the resources referencing uri directly.
+func (g *DependencyGraph) Dependents(uri string) []string {
+	var uris []string
+	for _, edge := range g.Edges {
+		if edge.To == uri && !slices.Contains(uris, edge.From) {
+			uris = append(uris, edge.From)
+		}
+	}
+	slices.Sort(uris)
+	return uris
+}
+
+// TopologicalOrder returns the node URIs with every resource after the
+// resources it references, in the order they can be compiled or published.
+// When references form a cycle, it fails with ErrCircularReference naming
+// the resources of one cycle.
+func (g *DependencyGraph) TopologicalOrder() ([]string, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		cycle := append(slices.Clone(cycles[0]), cycles[0][0])
+		return nil, fmt.Errorf("%w: %s", ErrCircularReference, strings.Join(cycle, " -> "))
+	}
+
+	pending := make(map[string]int, len(g.Nodes))
+	for _, node := range g.Nodes {
+		pending[node.URI] = len(g.Dependencies(node.URI))
+	}
+	order := make([]string, 0, len(g.Nodes))
+	for len(order) < len(g.Nodes) {
+		var ready []string
+		for uri, count := range pending {
+			if count == 0 {
+				ready = append(ready, uri)
+			}
+		}
+		slices.Sort(ready)
+		for _, uri := range ready {
+			delete(pending, uri)
+			for _, dependent := range g.Dependents(uri) {
+				pending[dependent]--
+			}
+		}
+		order = append(order, ready...)
+	}
+	return order, nil
+}
+
+// Cycles returns the groups of resources whose references lead back to
+// each other. Each group lists its URIs in reference order, starting with
+// the smallest, and groups are sorted by their first URI.
+func (g *DependencyGraph) Cycles() [][]string {
+	// Tarjan's strongly connected components.
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(uri string)
+	connect = func(uri string) {
+		index[uri] = len(index)
+		lowLink[uri] = index[uri]
+		stack = append(stack, uri)
+		onStack[uri] = true
+		for _, next := range g.Dependencies(uri) {
+			if _, seen := index[next]; !seen {
+				connect(next)
+				lowLink[uri] = min(lowLink[uri], lowLink[next])
+			} else if onStack[next] {
+				lowLink[uri] = min(lowLink[uri], index[next])
+			}
+		}
+		if lowLink[uri] != index[uri] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == uri {
+				break
+			}
+		}
+		if len(component) > 1 {
+			components = append(components, g.cyclePath(component))
+		}
+	}
+	for _, node := range g.Nodes {
+		if _, seen := index[node.URI]; !seen {
+			connect(node.URI)
+		}
+	}
+	slices.SortFunc(components, func(a, b []string) int { return strings.Compare(a[0], b[0]) })
+	return components
+}
+
+// cyclePath orders the resources of a strongly connected component along
+// references, starting with the smallest URI.
+func (g *DependencyGraph) cyclePath(component []string) []string {
+	path := []string{slices.Min(component)}
+	for len(path) < len(component) {
+		last := path[len(path)-1]
+		for _, next := range g.Dependencies(last) {
+			if slices.Contains(component, next) && !slices.Contains(path, next) {
+				path = append(path, next)
+				break
+			}
+		}
+		if path[len(path)-1] == last {
+			// The remaining resources join the cycle through others.
+			for _, uri := range slices.Sorted(slices.Values(component)) {
+				if !slices.Contains(path, uri) {
+					path = append(path, uri)
+				}
+			}
+		}
+	}
+	return path
+}
+
+// DOT returns the graph in the Graphviz DOT language. Edges are labeled with
+// the location of the reference, and unresolved resources are dashed.
+func (g *DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph schemas {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %s", dotQuote(node.URI))
+		if node.Schema == nil {
+			b.WriteString(" [style=dashed]")
+		}
+		b.WriteString(";\n")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(edgeLabel(edge)))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart. Edges are labeled with
+// the location of the reference, and unresolved resources are dashed.
+func (g *DependencyGraph) Mermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		ids[node.URI] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "  %s[%s]\n", ids[node.URI], mermaidQuote(node.URI))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[edge.From], mermaidQuote(edgeLabel(edge)), ids[edge.To])
+	}
+	for _, node := range g.Nodes {
+		if node.Schema == nil {
+			fmt.Fprintf(&b, "  style %s stroke-dasharray: 5 5\n", ids[node.URI])
+		}
+	}
+	return b.String()
+}
+
+// edgeLabel returns how exports label edge, "#/properties/a" for a "$ref"
+// and "#/properties/a ($dynamicRef)" for a "$dynamicRef".
+func edgeLabel(edge DependencyEdge) string {
+	if edge.Keyword == "$dynamicRef" {
+		return "#" + edge.Location + " ($dynamicRef)"
+	}
+	return "#" + edge.Location
+}
+
+func dotQuote(uri string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(uri) + `"`
+}
+
+func mermaidQuote(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, "#quot;") + `"`
+}
+
+// graphBuilder collects the resources and references of a DependencyGraph.
+type graphBuilder struct {
+	nodes map[string]*Schema
+	ids   map[*Schema]string // Node IDs of the documents without a URI.
+	edges []DependencyEdge
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{nodes: make(map[string]*Schema), ids: make(map[*Schema]string)}
+}
+
+// id returns the node ID of resource: its URI, or "anonymous:N" for the Nth
+// document without one.
+func (b *graphBuilder) id(resource *Schema) string {
+	if resource.uri != "" {
+		return resource.uri
+	}
+	id, ok := b.ids[resource]
+	if !ok {
+		id = fmt.Sprintf("anonymous:%d", len(b.ids)+1)
+		b.ids[resource] = id
+	}
+	return id
+}
+
+// resourceOf returns the resource s belongs to: the nearest schema with its
+// own URI, or the document root.
+func resourceOf(s *Schema) *Schema {
+	for ; s.parent != nil; s = s.parent {
+		if s.uri != "" {
+			return s
+		}
+	}
+	return s
+}
+
+// visit adds the resource and the resources nested in it, then the
+// resources their references lead to.
+func (b *graphBuilder) visit(resource *Schema) {
+	from := b.id(resource)
+	if existing, ok := b.nodes[from]; ok && existing != nil {
+		return
+	}
+	b.nodes[from] = resource
+
+	var targets []*Schema
+	var walk func(s *Schema, location string)
+	walk = func(s *Schema, location string) {
+		for _, ref := range []struct {
+			keyword, value string
+			resolved       *Schema
+		}{
+			{"$ref", s.Ref, s.ResolvedRef},
+			{"$dynamicRef", s.DynamicRef, s.ResolvedDynamicRef},
+		} {
+			if ref.value == "" {
+				continue
+			}
+			edge := DependencyEdge{From: from, Location: location, Keyword: ref.keyword, Ref: ref.value}
+			if ref.resolved != nil {
+				target := resourceOf(ref.resolved)
+				if target == resource {
+					continue
+				}
+				edge.To = b.id(target)
+				targets = append(targets, target)
+			} else {
+				edge.To = s.unresolvedReferenceTargetURI(ref.value)
+				if edge.To == "" || edge.To == resource.uri {
+					continue
+				}
+				if _, ok := b.nodes[edge.To]; !ok {
+					b.nodes[edge.To] = nil
+				}
+			}
+			b.edges = append(b.edges, edge)
+		}
+		for _, child := range strictChildren(s, location) {
+			if child.schema.uri != "" {
+				targets = append(targets, child.schema)
+				continue
+			}
+			walk(child.schema, child.location)
+		}
+	}
+	walk(resource, "")
+
+	for _, target := range targets {
+		b.visit(target)
+	}
+}
+
+func (b *graphBuilder) graph() *DependencyGraph {
+	graph := &DependencyGraph{Edges: b.edges}
+	for _, uri := range slices.Sorted(maps.Keys(b.nodes)) {
+		graph.Nodes = append(graph.Nodes, DependencyNode{URI: uri, Schema: b.nodes[uri]})
+	}
+	slices.SortStableFunc(graph.Edges, func(a, b DependencyEdge) int {
+		if c := strings.Compare(a.From, b.From); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Location, b.Location)
+	})
+	return graph
+}
@@ -0,0 +1,134 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compileGraphSchemas(t *testing.T, sources ...string) *Compiler {
+	t.Helper()
+	compiler := NewCompiler()
+	delete(compiler.Loaders, "https")
+	for _, source := range sources {
+		_, err := compiler.Compile([]byte(source))
+		require.NoError(t, err)
+	}
+	return compiler
+}
+
+func TestSchemaDependencyGraph(t *testing.T) {
+	compiler := compileGraphSchemas(t,
+		`{"$id": "https://example.com/name.json", "type": "string"}`,
+		`{"$id": "https://example.com/user.json", "properties": {"name": {"$ref": "name.json"}, "self": {"$ref": "#"}, "address": {"$id": "address.json", "properties": {"city": {"$ref": "name.json"}}}}}`,
+		`{"$id": "https://example.com/team.json", "items": {"$ref": "user.json"}, "properties": {"lead": {"$ref": "user.json#/properties/name"}, "logo": {"$ref": "missing.json#/$defs/logo"}}}`,
+	)
+	team, err := compiler.Schema("https://example.com/team.json")
+	require.NoError(t, err)
+	name, err := compiler.Schema("https://example.com/name.json")
+	require.NoError(t, err)
+
+	graph := team.DependencyGraph()
+	var uris []string
+	for _, node := range graph.Nodes {
+		uris = append(uris, node.URI)
+	}
+	assert.Equal(t, []string{
+		"https://example.com/address.json",
+		"https://example.com/missing.json",
+		"https://example.com/name.json",
+		"https://example.com/team.json",
+		"https://example.com/user.json",
+	}, uris)
+	assert.Nil(t, graph.Nodes[1].Schema, "unresolved resources have no schema")
+	assert.Same(t, name, graph.Nodes[2].Schema)
+
+	assert.Equal(t, []DependencyEdge{
+		{From: "https://example.com/address.json", Location: "/properties/city", Keyword: "$ref", Ref: "name.json", To: "https://example.com/name.json"},
+		{From: "https://example.com/team.json", Location: "/items", Keyword: "$ref", Ref: "user.json", To: "https://example.com/user.json"},
+		{From: "https://example.com/team.json", Location: "/properties/lead", Keyword: "$ref", Ref: "user.json#/properties/name", To: "https://example.com/user.json"},
+		{From: "https://example.com/team.json", Location: "/properties/logo", Keyword: "$ref", Ref: "missing.json#/$defs/logo", To: "https://example.com/missing.json"},
+		{From: "https://example.com/user.json", Location: "/properties/name", Keyword: "$ref", Ref: "name.json", To: "https://example.com/name.json"},
+	}, graph.Edges)
+
+	assert.Equal(t, []string{"https://example.com/missing.json", "https://example.com/user.json"}, graph.Dependencies("https://example.com/team.json"))
+	assert.Equal(t, []string{"https://example.com/address.json", "https://example.com/user.json"}, graph.Dependents("https://example.com/name.json"))
+
+	order, err := graph.TopologicalOrder()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://example.com/missing.json",
+		"https://example.com/name.json",
+		"https://example.com/address.json",
+		"https://example.com/user.json",
+		"https://example.com/team.json",
+	}, order)
+	assert.Empty(t, graph.Cycles())
+}
+
+func TestCompilerDependencyGraphCycles(t *testing.T) {
+	compiler := compileGraphSchemas(t,
+		`{"$id": "https://example.com/a.json", "$ref": "b.json"}`,
+		`{"$id": "https://example.com/b.json", "items": {"$ref": "c.json"}}`,
+		`{"$id": "https://example.com/c.json", "properties": {"a": {"$dynamicRef": "a.json"}}}`,
+		`{"$id": "https://example.com/lone.json"}`,
+	)
+
+	graph := compiler.DependencyGraph()
+	assert.Len(t, graph.Nodes, 4)
+	assert.Equal(t, [][]string{{"https://example.com/a.json", "https://example.com/b.json", "https://example.com/c.json"}}, graph.Cycles())
+	assert.Equal(t, []string{"https://example.com/c.json"}, graph.Dependents("https://example.com/a.json"))
+
+	_, err := graph.TopologicalOrder()
+	require.ErrorIs(t, err, ErrCircularReference)
+	assert.Contains(t, err.Error(), "https://example.com/a.json -> https://example.com/b.json -> https://example.com/c.json -> https://example.com/a.json")
+}
+
+func TestDependencyGraphExports(t *testing.T) {
+	compiler := compileGraphSchemas(t,
+		`{"$id": "https://example.com/name.json", "type": "string"}`,
+		`{"$id": "https://example.com/user.json", "properties": {"name": {"$ref": "name.json"}, "tags": {"$dynamicRef": "tags.json"}}}`,
+	)
+	user, err := compiler.Schema("https://example.com/user.json")
+	require.NoError(t, err)
+	graph := user.DependencyGraph()
+
+	assert.Equal(t, `digraph schemas {
+  rankdir=LR;
+  node [shape=box];
+  "https://example.com/name.json";
+  "https://example.com/tags.json" [style=dashed];
+  "https://example.com/user.json";
+  "https://example.com/user.json" -> "https://example.com/name.json" [label="#/properties/name"];
+  "https://example.com/user.json" -> "https://example.com/tags.json" [label="#/properties/tags ($dynamicRef)"];
+}
+`, graph.DOT())
+
+	assert.Equal(t, `flowchart LR
+  n0["https://example.com/name.json"]
+  n1["https://example.com/tags.json"]
+  n2["https://example.com/user.json"]
+  n2 -->|"#/properties/name"| n0
+  n2 -->|"#/properties/tags ($dynamicRef)"| n1
+  style n1 stroke-dasharray: 5 5
+`, graph.Mermaid())
+}
+
+func TestCompilerDependencyGraphAnonymousDocuments(t *testing.T) {
+	compiler := compileGraphSchemas(t, `{"$id": "https://example.com/name.json", "type": "string"}`)
+	first, err := compiler.Compile([]byte(`{"properties": {"name": {"$ref": "https://example.com/name.json"}}}`))
+	require.NoError(t, err)
+	second, err := compiler.Compile([]byte(`{"items": {"$ref": "https://example.com/name.json"}}`))
+	require.NoError(t, err)
+
+	graph := compiler.DependencyGraph()
+	require.Len(t, graph.Nodes, 3, "documents without $id are distinct nodes")
+	assert.Equal(t, "anonymous:1", graph.Nodes[0].URI)
+	assert.Same(t, first, graph.Nodes[0].Schema)
+	assert.Equal(t, "anonymous:2", graph.Nodes[1].URI)
+	assert.Same(t, second, graph.Nodes[1].Schema)
+	assert.Equal(t, []string{"anonymous:1", "anonymous:2"}, graph.Dependents("https://example.com/name.json"))
+	assert.Equal(t, "anonymous:1", second.DependencyGraph().Nodes[0].URI, "numbering is per graph")
+	assert.Contains(t, graph.DOT(), `"anonymous:2" -> "https://example.com/name.json" [label="#/items"];`)
+}
@@ -0,0 +1,269 @@
+package jsonschema
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// defaultHTTPTimeout bounds each remote schema fetch unless
+// HTTPLoaderOptions.Timeout says otherwise.
+const defaultHTTPTimeout = 10 * time.Second
+
+// HTTPLoaderOptions is the fetch policy of HTTPLoader. The zero value fetches
+// any URL with a 10s timeout and follows up to 10 redirects, like the loaders
+// a new Compiler registers for "http" and "https".
+type HTTPLoaderOptions struct {
+	// DisableNetwork makes every fetch fail with ErrNetworkDisabled, which
+	// wraps ErrReferenceResolution. Schemas must then be added with Compile,
+	// SetSchema or RegisterFS.
+	DisableNetwork bool
+
+	// AllowedHosts lists the hosts schemas may be fetched from. An entry
+	// "*.example.com" allows every subdomain of example.com. A URL is allowed
+	// when it matches AllowedHosts or AllowedURLPrefixes; when both are
+	// empty, every URL is allowed.
+	AllowedHosts []string
+
+	// AllowedURLPrefixes lists the URL prefixes schemas may be fetched from,
+	// such as "https://schemas.example.com/public/". A URL matches when its
+	// scheme and host equal the prefix's and its path is within the prefix
+	// path by whole segments; URLs with user info never match.
+	AllowedURLPrefixes []string
+
+	// BlockPrivateNetworks refuses connections to loopback, private,
+	// link-local, carrier-grade NAT, NAT64 and unspecified addresses. The check applies to the address
+	// a host name resolves to, so DNS names pointing inside the network are
+	// refused too. Proxies from the environment are not used in this mode.
+	BlockPrivateNetworks bool
+
+	// MaxResponseSize caps the size of a schema document in bytes. Zero means
+	// no limit.
+	MaxResponseSize int64
+
+	// MaxRedirects caps the number of redirects followed per fetch. Zero
+	// means 10; a negative value refuses all redirects. Redirect targets must
+	// pass the allowlists as well.
+	MaxRedirects int
+
+	// Timeout bounds each fetch, including redirects and reading the body.
+	// Zero means 10s.
+	Timeout time.Duration
+}
+
+// HTTPLoader returns a loader for "http" and "https" URLs that enforces opts.
+// Register it for both schemes, or use Compiler.SetHTTPLoaderOptions:
+//
+//	loader := jsonschema.HTTPLoader(jsonschema.HTTPLoaderOptions{
+//		AllowedHosts:         []string{"schemas.example.com"},
+//		BlockPrivateNetworks: true,
+//		MaxResponseSize:      1 << 20,
+//	})
+//	compiler.RegisterLoader("http", loader).RegisterLoader("https", loader)
+func HTTPLoader(opts HTTPLoaderOptions) func(url string) (io.ReadCloser, error) {
+	if opts.DisableNetwork {
+		return func(url string) (io.ReadCloser, error) {
+			return nil, fmt.Errorf("%w: %s", ErrNetworkDisabled, url)
+		}
+	}
+
+	client := opts.client()
+	return func(rawURL string) (io.ReadCloser, error) {
+		resp, err := opts.get(client, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		return typedBody{ReadCloser: resp.Body, contentType: resp.Header.Get("Content-Type")}, nil
+	}
+}
+
+// get fetches rawURL with the extra request header. Any status but 200 is an
+// error, except 304 in reply to a conditional request. The body of a 200
+// response is limited to MaxResponseSize.
+func (opts HTTPLoaderOptions) get(client *http.Client, rawURL string, header http.Header) (*http.Response, error) {
+	if err := opts.checkURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(req.Header, header)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNetworkFetch, err)
+	}
+
+	if resp.StatusCode == http.StatusNotModified && len(header) > 0 {
+		return resp, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %w", ErrInvalidStatusCode, &HTTPStatusError{
+			URL:        rawURL,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		})
+	}
+
+	if opts.MaxResponseSize > 0 {
+		if resp.ContentLength > opts.MaxResponseSize {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("%w: %s is %d bytes, limit is %d", ErrResponseTooLarge, rawURL, resp.ContentLength, opts.MaxResponseSize)
+		}
+		resp.Body = &limitedBody{body: resp.Body, url: rawURL, remaining: opts.MaxResponseSize}
+	}
+	return resp, nil
+}
+
+// SetHTTPLoaderOptions registers HTTPLoader(opts) for the "http" and "https"
+// schemes, replacing the default loaders.
+func (c *Compiler) SetHTTPLoaderOptions(opts HTTPLoaderOptions) *Compiler {
+	loader := HTTPLoader(opts)
+	c.RegisterLoader("http", loader)
+	c.RegisterLoader("https", loader)
+	return c
+}
+
+// client builds the HTTP client enforcing the redirect and network policy.
+func (opts HTTPLoaderOptions) client() *http.Client {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+	maxRedirects := opts.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = 10
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, len(via)-1)
+			}
+			return opts.checkURL(req.URL.String())
+		},
+	}
+
+	if opts.BlockPrivateNetworks {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   blockPrivateAddress,
+		}
+		transport.DialContext = dialer.DialContext
+		client.Transport = transport
+	}
+
+	return client
+}
+
+// checkURL reports ErrURLNotAllowed for URLs outside the allowlists.
+func (opts HTTPLoaderOptions) checkURL(rawURL string) error {
+	if len(opts.AllowedHosts) == 0 && len(opts.AllowedURLPrefixes) == 0 {
+		return nil
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		for _, prefix := range opts.AllowedURLPrefixes {
+			if matchURLPrefix(prefix, u) {
+				return nil
+			}
+		}
+		host := strings.ToLower(u.Hostname())
+		for _, allowed := range opts.AllowedHosts {
+			if matchHost(strings.ToLower(allowed), host) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrURLNotAllowed, rawURL)
+}
+
+// matchURLPrefix matches u against an allowlist prefix by scheme, host and
+// whole path segments, so "https://example.com/public" allows neither
+// "https://example.com.evil.net/public" nor "https://example.com/publicity".
+func matchURLPrefix(prefix string, u *url.URL) bool {
+	allowed, err := url.Parse(prefix)
+	if err != nil || u.User != nil ||
+		!strings.EqualFold(allowed.Scheme, u.Scheme) || !strings.EqualFold(allowed.Host, u.Host) {
+		return false
+	}
+	allowedPath := strings.TrimSuffix(allowed.Path, "/")
+	requested := path.Clean("/" + u.Path)
+	return allowedPath == "" || requested == allowedPath || strings.HasPrefix(requested, allowedPath+"/")
+}
+
+// matchHost matches host against an allowlist entry, where "*.example.com"
+// matches the subdomains of example.com.
+func matchHost(pattern, host string) bool {
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok && strings.HasPrefix(suffix, ".") {
+		return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+	}
+	return pattern == host
+}
+
+// blockPrivateAddress is a net.Dialer Control function. It runs after DNS
+// resolution, so address holds the IP actually being connected to.
+func blockPrivateAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		slices.ContainsFunc(blockedPrefixes, func(prefix netip.Prefix) bool { return prefix.Contains(ip) }) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+	}
+	return nil
+}
+
+// blockedPrefixes are the internal ranges netip has no predicate for.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "This network", RFC 1122.
+	netip.MustParsePrefix("100.64.0.0/10"),  // Carrier-grade NAT, RFC 6598.
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64 to IPv4 addresses, RFC 6052.
+	netip.MustParsePrefix("64:ff9b:1::/48"), // Local-use NAT64, RFC 8215.
+}
+
+// limitedBody fails reads once more than remaining bytes have been read.
+type limitedBody struct {
+	body      io.ReadCloser
+	url       string
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrResponseTooLarge, b.url)
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,190 @@
+package jsonschema
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchemaServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/name.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"type": "string"}`)
+	})
+	mux.HandleFunc("/large.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"description": "`+strings.Repeat("x", 4096)+`"}`)
+	})
+	mux.HandleFunc("/redirect.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/name.json", http.StatusFound)
+	})
+	mux.HandleFunc("/loop.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop.json", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPLoaderDefaults(t *testing.T) {
+	server := newSchemaServer(t)
+
+	schema, err := NewCompiler().Schema(server.URL + "/redirect.json")
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(1).IsValid())
+}
+
+func TestHTTPLoaderNetworkDisabled(t *testing.T) {
+	server := newSchemaServer(t)
+	compiler := NewCompiler().SetHTTPLoaderOptions(HTTPLoaderOptions{DisableNetwork: true})
+
+	_, err := compiler.Schema(server.URL + "/name.json")
+	require.ErrorIs(t, err, ErrNetworkDisabled)
+	require.ErrorIs(t, err, ErrReferenceResolution)
+
+	schema, err := compiler.Compile([]byte(`{"$ref": "` + server.URL + `/name.json"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/name.json"}, schema.UnresolvedReferenceURIs())
+}
+
+func TestHTTPLoaderAllowlists(t *testing.T) {
+	server := newSchemaServer(t)
+
+	tests := []struct {
+		name    string
+		opts    HTTPLoaderOptions
+		allowed bool
+	}{
+		{name: "host", opts: HTTPLoaderOptions{AllowedHosts: []string{"127.0.0.1"}}, allowed: true},
+		{name: "other host", opts: HTTPLoaderOptions{AllowedHosts: []string{"schemas.example.com"}}},
+		{name: "wildcard", opts: HTTPLoaderOptions{AllowedHosts: []string{"*.0.0.1"}}, allowed: true},
+		{name: "prefix", opts: HTTPLoaderOptions{AllowedURLPrefixes: []string{server.URL + "/"}}, allowed: true},
+		{name: "other prefix", opts: HTTPLoaderOptions{AllowedURLPrefixes: []string{server.URL + "/public/"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCompiler().SetHTTPLoaderOptions(tt.opts).Schema(server.URL + "/name.json")
+			if tt.allowed {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrURLNotAllowed)
+			}
+		})
+	}
+}
+
+func TestHTTPLoaderRedirectsRecheckAllowlist(t *testing.T) {
+	server := newSchemaServer(t)
+	opts := HTTPLoaderOptions{AllowedURLPrefixes: []string{server.URL + "/redirect.json"}}
+
+	_, err := NewCompiler().SetHTTPLoaderOptions(opts).Schema(server.URL + "/redirect.json")
+	require.ErrorIs(t, err, ErrURLNotAllowed)
+}
+
+func TestHTTPLoaderMaxRedirects(t *testing.T) {
+	server := newSchemaServer(t)
+
+	_, err := NewCompiler().SetHTTPLoaderOptions(HTTPLoaderOptions{MaxRedirects: 3}).Schema(server.URL + "/loop.json")
+	require.ErrorIs(t, err, ErrTooManyRedirects)
+
+	_, err = NewCompiler().SetHTTPLoaderOptions(HTTPLoaderOptions{MaxRedirects: -1}).Schema(server.URL + "/redirect.json")
+	require.ErrorIs(t, err, ErrTooManyRedirects)
+}
+
+func TestHTTPLoaderMaxResponseSize(t *testing.T) {
+	server := newSchemaServer(t)
+	compiler := NewCompiler().SetHTTPLoaderOptions(HTTPLoaderOptions{MaxResponseSize: 1024})
+
+	_, err := compiler.Schema(server.URL + "/large.json")
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+
+	_, err = compiler.Schema(server.URL + "/name.json")
+	require.NoError(t, err)
+}
+
+func TestHTTPLoaderMaxResponseSizeWithoutContentLength(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		for range 64 {
+			_, _ = io.WriteString(w, strings.Repeat(" ", 64))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	body, err := HTTPLoader(HTTPLoaderOptions{MaxResponseSize: 1024})(server.URL)
+	require.NoError(t, err)
+	defer func() { _ = body.Close() }()
+	_, err = io.ReadAll(body)
+	require.ErrorIs(t, err, ErrResponseTooLarge)
+}
+
+func TestHTTPLoaderBlocksPrivateNetworks(t *testing.T) {
+	server := newSchemaServer(t)
+	localhostURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	for _, rawURL := range []string{server.URL + "/name.json", localhostURL + "/name.json"} {
+		_, err := HTTPLoader(HTTPLoaderOptions{BlockPrivateNetworks: true})(rawURL)
+		require.ErrorIs(t, err, ErrBlockedAddress, rawURL)
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	assert.True(t, matchHost("example.com", "example.com"))
+	assert.False(t, matchHost("example.com", "api.example.com"))
+	assert.True(t, matchHost("*.example.com", "api.example.com"))
+	assert.False(t, matchHost("*.example.com", "example.com"))
+	assert.False(t, matchHost("*.example.com", "badexample.com"))
+}
+
+func TestMatchURLPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		rawURL  string
+		allowed bool
+	}{
+		{"https://schemas.example.com", "https://schemas.example.com/a.json", true},
+		{"https://schemas.example.com", "https://SCHEMAS.example.com/a.json", true},
+		{"https://schemas.example.com", "https://schemas.example.com.evil.net/a.json", false},
+		{"https://schemas.example.com", "https://schemas.example.com@evil.net/a.json", false},
+		{"https://schemas.example.com", "http://schemas.example.com/a.json", false},
+		{"https://schemas.example.com", "https://schemas.example.com:8443/a.json", false},
+		{"https://example.com/public/", "https://example.com/public/a.json", true},
+		{"https://example.com/public", "https://example.com/public/a.json", true},
+		{"https://example.com/public", "https://example.com/publicity.json", false},
+		{"https://example.com/public/", "https://example.com/public/../private.json", false},
+		{"https://example.com/a.json", "https://example.com/a.json", true},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.rawURL)
+		require.NoError(t, err)
+		assert.Equal(t, tt.allowed, matchURLPrefix(tt.prefix, u), "%s %s", tt.prefix, tt.rawURL)
+	}
+}
+
+func TestBlockPrivateAddress(t *testing.T) {
+	for address, blocked := range map[string]bool{
+		"127.0.0.1:80":            true,
+		"10.1.2.3:80":             true,
+		"0.1.2.3:80":              true,
+		"100.64.0.1:80":           true,
+		"100.127.255.254:80":      true,
+		"[64:ff9b::a01:203]:80":   true,
+		"[::ffff:169.254.1.1]:80": true,
+		"100.128.0.1:80":          false,
+		"93.184.216.34:443":       false,
+		"[2606:4700::1111]:443":   false,
+	} {
+		err := blockPrivateAddress("tcp", address, nil)
+		if blocked {
+			assert.ErrorIs(t, err, ErrBlockedAddress, address)
+		} else {
+			assert.NoError(t, err, address)
+		}
+	}
+}
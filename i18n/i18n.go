@@ -8,6 +8,8 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io/fs"
+	"path"
 	"slices"
 	"strings"
 	"sync"
@@ -17,8 +19,13 @@ import (
 	"github.com/kaptinlin/jsonschema"
 )
 
-// ErrUnsupportedLocale is returned by New for a locale outside the built-in catalog.
-var ErrUnsupportedLocale = errors.New("unsupported locale")
+var (
+	// ErrUnsupportedLocale is returned by New for a locale outside the built-in catalog.
+	ErrUnsupportedLocale = errors.New("unsupported locale")
+
+	// ErrNoCatalogFiles is returned by NewFromFS when the pattern matches no file.
+	ErrNoCatalogFiles = errors.New("no catalog files matched")
+)
 
 //go:embed locales/*.json
 var localesFS embed.FS
@@ -58,14 +65,165 @@ func New(locale string) (jsonschema.Translator, error) {
 		return nil, err
 	}
 
-	return &translator{localizer: bundle.NewLocalizer(locale)}, nil
+	return newTranslator(bundle, locale), nil
+}
+
+// Catalog holds the built-in locale catalogs together with catalogs loaded
+// from a filesystem and the configured fallback chains. A Catalog is
+// read-only once constructed and safe for concurrent use.
+type Catalog struct {
+	bundle  *goi18n.I18n
+	locales []string
+}
+
+// Option configures a Catalog.
+type Option func(*catalogConfig)
+
+type catalogConfig struct {
+	fallbacks map[string][]string
+}
+
+// WithFallback declares the locales consulted, in order, when locale has no
+// translation for a code, e.g. WithFallback("de-AT", "de-DE", "en"). English
+// is always consulted last, so listing it is optional. Locales named here
+// need no catalog file of their own.
+func WithFallback(locale string, fallbacks ...string) Option {
+	return func(cfg *catalogConfig) {
+		if cfg.fallbacks == nil {
+			cfg.fallbacks = make(map[string][]string)
+		}
+		cfg.fallbacks[locale] = slices.Clone(fallbacks)
+	}
+}
+
+// NewFromFS returns a Catalog that adds the catalogs matching pattern in fsys
+// to the built-in ones. Each file is a flat JSON object of code to message
+// template, named after its locale ("nl-NL.json", "it.json"); a file for a
+// built-in locale overrides the built-in messages it redefines.
+func NewFromFS(fsys fs.FS, pattern string, opts ...Option) (*Catalog, error) {
+	if fsys == nil {
+		return nil, fmt.Errorf("load locale catalogs: %w", fs.ErrInvalid)
+	}
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("load locale catalogs: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrNoCatalogFiles, pattern)
+	}
+
+	cfg := &catalogConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	catalogLocales := slices.Clone(locales)
+	for _, file := range files {
+		catalogLocales = appendLocale(catalogLocales, strings.TrimSuffix(path.Base(file), path.Ext(file)))
+	}
+
+	fallbacks := make(map[string][]string, len(cfg.fallbacks))
+	for locale, chain := range cfg.fallbacks {
+		catalogLocales = appendLocale(catalogLocales, locale)
+		var explicit []string
+		for _, fallback := range chain {
+			catalogLocales = appendLocale(catalogLocales, fallback)
+			// The default locale is the implicit end of every chain.
+			if !sameLocale(fallback, defaultLocale) {
+				explicit = append(explicit, fallback)
+			}
+		}
+		fallbacks[locale] = explicit
+	}
+
+	bundle, err := goi18n.NewBundle(
+		defaultLocale,
+		goi18n.WithLocales(catalogLocales[1:]...),
+		goi18n.WithFallback(fallbacks),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create locale bundle: %w", err)
+	}
+	if err := bundle.LoadFS(localesFS, "locales/*.json"); err != nil {
+		return nil, fmt.Errorf("load embedded locales: %w", err)
+	}
+	if err := bundle.LoadFS(fsys, pattern); err != nil {
+		return nil, fmt.Errorf("load locale catalogs: %w", err)
+	}
+
+	return &Catalog{bundle: bundle, locales: catalogLocales}, nil
+}
+
+// Locales returns the locales the catalog can render; the default locale is first.
+func (c *Catalog) Locales() []string {
+	return slices.Clone(c.locales)
+}
+
+// New returns a Translator bound to one of the catalog's locales. Like the
+// package-level New, an unknown locale is an error.
+func (c *Catalog) New(locale string) (jsonschema.Translator, error) {
+	index := slices.IndexFunc(c.locales, func(candidate string) bool { return sameLocale(candidate, locale) })
+	if index < 0 {
+		return nil, fmt.Errorf("%w: %q (supported: %s)", ErrUnsupportedLocale, locale, strings.Join(c.locales, ", "))
+	}
+	return newTranslator(c.bundle, c.locales[index]), nil
+}
+
+// Negotiate returns the Translator for the catalog locale that best matches
+// an HTTP Accept-Language header value, falling back to the default locale.
+func (c *Catalog) Negotiate(acceptLanguage string) jsonschema.Translator {
+	return negotiate(c.bundle, c.locales, acceptLanguage)
+}
+
+// Negotiate returns the Translator for the built-in locale that best matches
+// an HTTP Accept-Language header value, falling back to the default locale.
+func Negotiate(acceptLanguage string) jsonschema.Translator {
+	bundle, err := loadBundle()
+	if err != nil {
+		return nil
+	}
+	return negotiate(bundle, locales, acceptLanguage)
+}
+
+func negotiate(bundle *goi18n.I18n, available []string, acceptLanguage string) jsonschema.Translator {
+	matched := bundle.MatchAvailableLocale(acceptLanguage)
+	for _, locale := range available {
+		if sameLocale(locale, matched) {
+			return newTranslator(bundle, locale)
+		}
+	}
+	return newTranslator(bundle, defaultLocale)
+}
+
+// appendLocale adds locale unless an equivalent spelling is already listed.
+func appendLocale(list []string, locale string) []string {
+	if slices.ContainsFunc(list, func(existing string) bool { return sameLocale(existing, locale) }) {
+		return list
+	}
+	return append(list, locale)
+}
+
+// sameLocale compares locale tags ignoring case and "_" versus "-".
+func sameLocale(a, b string) bool {
+	return strings.EqualFold(strings.ReplaceAll(a, "_", "-"), strings.ReplaceAll(b, "_", "-"))
+}
+
+func newTranslator(bundle *goi18n.I18n, locale string) *translator {
+	return &translator{locale: locale, localizer: bundle.NewLocalizer(locale)}
 }
 
 // translator adapts a go-i18n Localizer to the jsonschema.Translator interface.
+// It also implements jsonschema.LocaleTranslator so custom "errorMessage"
+// variants follow the same locale.
 type translator struct {
+	locale    string
 	localizer *goi18n.Localizer
 }
 
+func (t *translator) Locale() string {
+	return t.locale
+}
+
 func (t *translator) Translate(code string, params map[string]any) (string, bool) {
 	result, err := t.localizer.Lookup(code, goi18n.Vars(params))
 	if result.Source == goi18n.TranslationSourceMissing {
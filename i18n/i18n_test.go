@@ -1,8 +1,15 @@
 package i18n_test
 
 import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/go-json-experiment/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -79,8 +86,8 @@ func TestTranslateMissingKeyFallsBackToEnglish(t *testing.T) {
 	_, ok := zh.Translate("nonexistent_error_code", nil)
 	assert.False(t, ok)
 
-	evalErr := jsonschema.NewEvaluationError("format", "invalid_json", "Invalid JSON format")
-	assert.Equal(t, "Invalid JSON format", evalErr.Localize(zh))
+	evalErr := jsonschema.NewEvaluationError("x-custom", "custom_mismatch", "Value fails the custom check")
+	assert.Equal(t, "Value fails the custom check", evalErr.Localize(zh))
 }
 
 func TestLocalizedValidationWorkflow(t *testing.T) {
@@ -127,3 +134,158 @@ func TestLocalizedValidationWorkflow(t *testing.T) {
 			"locale %s should render the same error count as English", locale)
 	}
 }
+
+func TestTranslatorSelectsErrorMessageLocale(t *testing.T) {
+	t.Parallel()
+
+	schema, err := jsonschema.NewCompiler().Compile([]byte(`{
+		"type": "string",
+		"pattern": "^[0-9]{5}$",
+		"errorMessage": {"pattern": {"en": "Please enter a valid postcode", "de-DE": "Bitte eine gültige Postleitzahl eingeben"}}
+	}`))
+	require.NoError(t, err)
+
+	de, err := i18n.New("de-DE")
+	require.NoError(t, err)
+
+	result := schema.Validate("abc")
+	assert.Equal(t, map[string]string{"pattern": "Bitte eine gültige Postleitzahl eingeben"}, result.LocalizedDetailedErrors(de))
+}
+
+func testCatalogFS() fstest.MapFS {
+	return fstest.MapFS{
+		"locales/nl-NL.json": {Data: []byte(`{"string_too_short": "Waarde moet minstens {min_length} tekens bevatten"}`)},
+		"locales/it.json":    {Data: []byte(`{"string_too_short": "Il valore deve contenere almeno {min_length} caratteri"}`)},
+		"locales/de-DE.json": {Data: []byte(`{"string_too_long": "Höchstens {max_length} Zeichen"}`)},
+	}
+}
+
+func TestNewFromFSAddsAndOverridesCatalogs(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := i18n.NewFromFS(testCatalogFS(), "locales/*.json")
+	require.NoError(t, err)
+	assert.Equal(t, "en", catalog.Locales()[0], "default locale is listed first")
+	assert.Contains(t, catalog.Locales(), "nl-NL")
+	assert.Contains(t, catalog.Locales(), "it")
+
+	nl, err := catalog.New("nl-NL")
+	require.NoError(t, err)
+	message, ok := nl.Translate("string_too_short", map[string]any{"min_length": 3})
+	assert.True(t, ok)
+	assert.Equal(t, "Waarde moet minstens 3 tekens bevatten", message)
+
+	message, ok = nl.Translate("string_too_long", map[string]any{"max_length": 3})
+	assert.True(t, ok, "codes missing from a partial catalog fall back to English")
+	assert.Equal(t, "Value should be at most 3 characters", message)
+
+	de, err := catalog.New("de-DE")
+	require.NoError(t, err)
+	message, _ = de.Translate("string_too_long", map[string]any{"max_length": 3})
+	assert.Equal(t, "Höchstens 3 Zeichen", message, "loaded catalogs override built-in messages")
+	message, _ = de.Translate("string_too_short", map[string]any{"min_length": 3})
+	assert.Equal(t, "Wert sollte mindestens 3 Zeichen lang sein", message, "other built-in messages are kept")
+
+	_, err = catalog.New("sv-SE")
+	require.ErrorIs(t, err, i18n.ErrUnsupportedLocale)
+}
+
+func TestNewFromFSFallbackChain(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := i18n.NewFromFS(fstest.MapFS{
+		"de-AT.json": {Data: []byte(`{"string_too_long": "Maximal {max_length} Zeichen"}`)},
+	}, "*.json", i18n.WithFallback("de-AT", "de-DE", "en"))
+	require.NoError(t, err)
+
+	at, err := catalog.New("de-AT")
+	require.NoError(t, err)
+
+	message, _ := at.Translate("string_too_long", map[string]any{"max_length": 3})
+	assert.Equal(t, "Maximal 3 Zeichen", message)
+	message, _ = at.Translate("string_too_short", map[string]any{"min_length": 3})
+	assert.Equal(t, "Wert sollte mindestens 3 Zeichen lang sein", message, "de-AT falls back to de-DE")
+}
+
+func TestNewFromFSRejectsEmptyPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := i18n.NewFromFS(fstest.MapFS{}, "locales/*.json")
+	require.ErrorIs(t, err, i18n.ErrNoCatalogFiles)
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := i18n.NewFromFS(testCatalogFS(), "locales/*.json")
+	require.NoError(t, err)
+
+	params := map[string]any{"min_length": 3}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"nl-NL,nl;q=0.9,en;q=0.5", "Waarde moet minstens 3 tekens bevatten"},
+		{"it-IT;q=0.8, sv;q=0.9", "Il valore deve contenere almeno 3 caratteri"},
+		{"sv-SE", "Value should be at least 3 characters"},
+		{"", "Value should be at least 3 characters"},
+	}
+	for _, tt := range tests {
+		message, _ := catalog.Negotiate(tt.header).Translate("string_too_short", params)
+		assert.Equal(t, tt.want, message, "Accept-Language %q", tt.header)
+	}
+
+	message, _ := i18n.Negotiate("fr-CA, en;q=0.5").Translate("string_too_short", params)
+	assert.Equal(t, "La valeur doit comporter au minimum 3 caractères", message)
+}
+
+func TestCatalogsCoverErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	placeholders := regexp.MustCompile(`\{(\w+)\}`)
+	placeholderSet := func(template string) []string {
+		var names []string
+		for _, match := range placeholders.FindAllStringSubmatch(template, -1) {
+			names = append(names, match[1])
+		}
+		slices.Sort(names)
+		return slices.Compact(names)
+	}
+
+	files, err := filepath.Glob("locales/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		locale := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(locale, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			var catalog map[string]string
+			require.NoError(t, json.Unmarshal(data, &catalog))
+
+			known := make(map[string]bool)
+			for _, info := range jsonschema.ErrorCodes() {
+				code := string(info.Code)
+				known[code] = true
+				if info.Template == "" {
+					continue // the message comes from the schema
+				}
+				message, ok := catalog[code]
+				if !assert.True(t, ok, "%s translates %s", locale, code) {
+					continue
+				}
+				assert.Equal(t, placeholderSet(info.Template), placeholderSet(message),
+					"%s uses the placeholders of %s", locale, code)
+				if locale == "en" {
+					assert.Equal(t, info.Template, message, "en matches the default template of %s", code)
+				}
+			}
+			for code := range catalog {
+				assert.True(t, known[code], "%s translates unknown code %s", locale, code)
+			}
+		})
+	}
+}
@@ -47,12 +47,12 @@ func evaluateItems(
 	}
 
 	if len(invalidIndexes) == 1 {
-		return results, NewEvaluationError("items", "item_mismatch", "Item at index {index} does not match the schema", map[string]any{
+		return results, newError("items", CodeItemMismatch, map[string]any{
 			"index": invalidIndexes[0],
 		})
 	}
 	if len(invalidIndexes) > 1 {
-		return results, NewEvaluationError("items", "items_mismatch", "Items at index {indexs} do not match the schema", map[string]any{
+		return results, newError("items", CodeItemsMismatch, map[string]any{
 			"indexs": strings.Join(invalidIndexes, ", "),
 		})
 	}
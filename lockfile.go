@@ -0,0 +1,184 @@
+package jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// LockfileVersion is the format version written by Lockfile.WriteFile.
+const LockfileVersion = 1
+
+// Lockfile pins the content of remote schema documents, so a document that
+// changes upstream fails compilation instead of silently changing
+// validation. Generate one with Compiler.Lockfile after compiling, commit it,
+// and enforce it with Compiler.SetLockfile.
+type Lockfile struct {
+	Version int `json:"version"`
+	// Schemas maps document URLs, without fragment, to their pins.
+	Schemas map[string]LockEntry `json:"schemas"`
+}
+
+// LockEntry is the pin of a single document.
+type LockEntry struct {
+	// SHA256 is the lowercase hex SHA-256 digest of the document bytes.
+	SHA256 string `json:"sha256"`
+	// Size is the document size in bytes.
+	Size int64 `json:"size"`
+}
+
+// NewLockEntry returns the pin of data.
+func NewLockEntry(data []byte) LockEntry {
+	sum := sha256.Sum256(data)
+	return LockEntry{SHA256: hex.EncodeToString(sum[:]), Size: int64(len(data))}
+}
+
+// LockfileMismatchError reports a fetched document whose content differs from
+// its pin. It matches ErrLockfileMismatch.
+type LockfileMismatchError struct {
+	URL      string
+	Expected LockEntry
+	Actual   LockEntry
+}
+
+// Error describes the pinned and fetched content.
+func (e *LockfileMismatchError) Error() string {
+	return fmt.Sprintf("%s: %s: pinned sha256 %s (%d bytes), fetched sha256 %s (%d bytes)",
+		ErrLockfileMismatch, e.URL, e.Expected.SHA256, e.Expected.Size, e.Actual.SHA256, e.Actual.Size)
+}
+
+// Unwrap returns ErrLockfileMismatch.
+func (e *LockfileMismatchError) Unwrap() error {
+	return ErrLockfileMismatch
+}
+
+// ReadLockfile reads a lockfile written by Lockfile.WriteFile.
+func ReadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDataRead, err)
+	}
+	var lockfile Lockfile
+	if err := unmarshalJSON(data, &lockfile); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+	}
+	if lockfile.Version != LockfileVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedLockfileVersion, lockfile.Version)
+	}
+	return &lockfile, nil
+}
+
+// WriteFile writes the lockfile as indented JSON with sorted URLs, so it
+// diffs cleanly under version control.
+func (l *Lockfile) WriteFile(path string) error {
+	data, err := marshalJSON(l, json.Deterministic(true), jsontext.WithIndent("  "))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDataEncode, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("%w: %w", ErrFileWrite, err)
+	}
+	return nil
+}
+
+// Lockfile returns the pins of every document fetched through a loader by
+// this compiler so far. Documents served by RegisterFS mounts are part of the
+// build and are not pinned.
+func (c *Compiler) Lockfile() *Lockfile {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return &Lockfile{Version: LockfileVersion, Schemas: maps.Clone(c.fetched)}
+}
+
+// SetLockfile enforces lockfile: a fetched document must be pinned and match
+// its pin. Otherwise Compile and CompileBatch fail with ErrLockfileMismatch
+// or ErrSchemaNotPinned. A nil lockfile turns enforcement off.
+func (c *Compiler) SetLockfile(lockfile *Lockfile) *Compiler {
+	c.mu.Lock()
+	c.lockfile = lockfile
+	c.lockErrors = nil
+	c.mu.Unlock()
+	return c
+}
+
+// pin records the content fetched for uri and checks it against the
+// lockfile. Violations are kept, so that compilation of every schema
+// referencing uri fails rather than only the one that triggered the fetch.
+func (c *Compiler) pin(uri string, data []byte) error {
+	entry := NewLockEntry(data)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fetched == nil {
+		c.fetched = make(map[string]LockEntry)
+	}
+	c.fetched[uri] = entry
+	if c.lockfile == nil {
+		return nil
+	}
+
+	var err error
+	if expected, ok := c.lockfile.Schemas[uri]; !ok {
+		err = fmt.Errorf("%w: %s", ErrSchemaNotPinned, uri)
+	} else if expected != entry {
+		err = &LockfileMismatchError{URL: uri, Expected: expected, Actual: entry}
+	}
+	if err != nil {
+		if c.lockErrors == nil {
+			c.lockErrors = make(map[string]error)
+		}
+		c.lockErrors[uri] = err
+	}
+	return err
+}
+
+// lockViolations returns the lockfile violations of the documents schema
+// references, directly or through other documents.
+func (c *Compiler) lockViolations(schema *Schema) error {
+	c.mu.RLock()
+	violations := maps.Clone(c.lockErrors)
+	c.mu.RUnlock()
+	if len(violations) == 0 {
+		return nil
+	}
+
+	found := make(map[string]error)
+	seen := make(map[*Schema]bool)
+	var visit func(*Schema)
+	visit = func(s *Schema) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+		for _, ref := range []struct {
+			ref      string
+			resolved *Schema
+		}{{s.Ref, s.ResolvedRef}, {s.DynamicRef, s.ResolvedDynamicRef}} {
+			switch {
+			case ref.ref == "":
+			case ref.resolved != nil:
+				visit(ref.resolved.rootSchema())
+			default:
+				uri := s.unresolvedReferenceTargetURI(ref.ref)
+				if err, ok := violations[uri]; ok {
+					found[uri] = err
+				}
+			}
+		}
+		s.walkNestedSchemas(visit)
+	}
+	visit(schema)
+
+	var errs []error
+	for _, uri := range slices.Sorted(maps.Keys(found)) {
+		errs = append(errs, found[uri])
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,127 @@
+package jsonschema
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPinnedServer serves name.json, which references common.json, whose
+// content can be swapped.
+func newPinnedServer(t *testing.T) (*httptest.Server, *atomic.Value) {
+	t.Helper()
+	common := &atomic.Value{}
+	common.Store(`{"type": "string"}`)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/name.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"$ref": "common.json", "minLength": 2}`)
+	})
+	mux.HandleFunc("/common.json", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, common.Load().(string))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, common
+}
+
+func TestCompilerLockfileRecordsFetchedSchemas(t *testing.T) {
+	server, _ := newPinnedServer(t)
+	compiler := NewCompiler()
+
+	_, err := compiler.Compile([]byte(`{"$ref": "` + server.URL + `/name.json"}`))
+	require.NoError(t, err)
+
+	lockfile := compiler.Lockfile()
+	assert.Equal(t, LockfileVersion, lockfile.Version)
+	assert.Equal(t, map[string]LockEntry{
+		server.URL + "/name.json":   NewLockEntry([]byte(`{"$ref": "common.json", "minLength": 2}`)),
+		server.URL + "/common.json": NewLockEntry([]byte(`{"type": "string"}`)),
+	}, lockfile.Schemas)
+}
+
+func TestCompilerLockfileEnforced(t *testing.T) {
+	server, common := newPinnedServer(t)
+	source := []byte(`{"properties": {"name": {"$ref": "` + server.URL + `/name.json"}}}`)
+
+	generator := NewCompiler()
+	_, err := generator.Compile(source)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "schemas.lock.json")
+	require.NoError(t, generator.Lockfile().WriteFile(path))
+
+	lockfile, err := ReadLockfile(path)
+	require.NoError(t, err)
+	assert.Equal(t, generator.Lockfile(), lockfile)
+
+	_, err = NewCompiler().SetLockfile(lockfile).Compile(source)
+	require.NoError(t, err, "unchanged content matches the pins")
+
+	common.Store(`{"type": "integer"}`)
+	_, err = NewCompiler().SetLockfile(lockfile).Compile(source)
+	require.ErrorIs(t, err, ErrLockfileMismatch)
+	var mismatch *LockfileMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, server.URL+"/common.json", mismatch.URL)
+	assert.Equal(t, lockfile.Schemas[server.URL+"/common.json"], mismatch.Expected)
+	assert.Equal(t, NewLockEntry([]byte(`{"type": "integer"}`)), mismatch.Actual)
+
+	_, err = NewCompiler().SetLockfile(lockfile).CompileBatch(map[string][]byte{"root.json": source})
+	require.ErrorIs(t, err, ErrLockfileMismatch)
+
+	compiler := NewCompiler().SetLockfile(lockfile)
+	_, err = compiler.Compile(source, "https://example.com/root.json")
+	require.ErrorIs(t, err, ErrLockfileMismatch)
+	_, cached := compiler.cachedSchema("https://example.com/root.json")
+	assert.False(t, cached, "a rejected schema is not cached")
+
+	_, err = compiler.CompileBatch(map[string][]byte{"https://example.com/batch.json": source})
+	require.ErrorIs(t, err, ErrLockfileMismatch)
+	_, cached = compiler.cachedSchema("https://example.com/batch.json")
+	assert.False(t, cached, "a rejected batch is not cached")
+}
+
+func TestCompilerLockfileRejectsUnpinnedSchemas(t *testing.T) {
+	server, _ := newPinnedServer(t)
+	compiler := NewCompiler().SetLockfile(&Lockfile{Version: LockfileVersion})
+
+	_, err := compiler.Compile([]byte(`{"$ref": "` + server.URL + `/name.json"}`))
+	require.ErrorIs(t, err, ErrSchemaNotPinned)
+}
+
+func TestReadLockfileVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schemas.lock.json")
+	require.NoError(t, (&Lockfile{Version: 2}).WriteFile(path))
+
+	_, err := ReadLockfile(path)
+	require.ErrorIs(t, err, ErrUnsupportedLockfileVersion)
+}
+
+func TestCompilerLockfileKeepsCachedSchemas(t *testing.T) {
+	server, common := newPinnedServer(t)
+	generator := NewCompiler()
+	_, err := generator.Compile([]byte(`{"$ref": "` + server.URL + `/name.json"}`))
+	require.NoError(t, err)
+
+	compiler := NewCompiler().SetLockfile(generator.Lockfile())
+	loader := compiler.Loaders["http"]
+	delete(compiler.Loaders, "http")
+	root, err := compiler.Compile([]byte(`{"$id": "https://example.com/root.json", "$ref": "` + server.URL + `/common.json"}`))
+	require.NoError(t, err)
+
+	compiler.RegisterLoader("http", loader)
+	common.Store(`{"type": "integer"}`)
+	_, err = compiler.Compile([]byte(`{"$ref": "` + server.URL + `/name.json"}`))
+	require.ErrorIs(t, err, ErrLockfileMismatch)
+
+	_, err = compiler.Compile([]byte(`{"$id": "https://example.com/root.json"}`))
+	require.ErrorIs(t, err, ErrLockfileMismatch, "the cached schema references a mismatched document")
+	schema, cached := compiler.cachedSchema("https://example.com/root.json")
+	assert.True(t, cached, "a failed compilation keeps the schemas cached before it")
+	assert.Same(t, root, schema)
+}
@@ -17,7 +17,7 @@ func evaluateMaxItems(schema *Schema, array []any) *EvaluationError {
 	if schema.MaxItems != nil {
 		if float64(len(array)) > *schema.MaxItems {
 			// If the array size exceeds the maximum allowed, construct and return an error.
-			return NewEvaluationError("maxItems", "items_too_long", "Value should have at most {max_items} items", map[string]any{
+			return newError("maxItems", CodeItemsTooLong, map[string]any{
 				"max_items": fmt.Sprintf("%.0f", *schema.MaxItems),
 				"count":     len(array),
 			})
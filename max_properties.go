@@ -13,7 +13,7 @@ func evaluateMaxProperties(schema *Schema, object map[string]any) *EvaluationErr
 	if schema.MaxProperties != nil {
 		actualCount := float64(len(object))
 		if actualCount > *schema.MaxProperties {
-			return NewEvaluationError("maxProperties", "too_many_properties", "Value should have at most {max_properties} properties", map[string]any{
+			return newError("maxProperties", CodeTooManyProperties, map[string]any{
 				"max_properties": *schema.MaxProperties,
 			})
 		}
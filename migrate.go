@@ -0,0 +1,427 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// MigrationIssue is a construct Migrate could not carry over exactly, or a
+// change worth reviewing.
+type MigrationIssue struct {
+	Location string // JSON Pointer to the schema within the source document.
+	Keyword  string
+	Message  string
+}
+
+func (i MigrationIssue) String() string {
+	return fmt.Sprintf("#%s: %s", i.Location, i.Message)
+}
+
+// Migrate rewrites a Draft-04, Draft-06, Draft-07 or Draft 2019-09 schema
+// document for a newer target dialect, keeping the order of its keys:
+//
+//   - "id" becomes "$id", or "$anchor" for a plain-name fragment;
+//   - boolean "exclusiveMinimum" and "exclusiveMaximum" become numeric;
+//   - "definitions" becomes "$defs" and "dependencies" becomes
+//     "dependentRequired" and "dependentSchemas", from Draft 2019-09 on;
+//   - array-form "items" becomes "prefixItems" and "additionalItems" becomes
+//     "items" in Draft 2020-12;
+//   - "$schema" names the target dialect;
+//   - "$ref" pointers into the document follow the renamed keywords.
+//
+// The document's dialect comes from "$schema"; without it the document is
+// taken for Draft-04 through Draft-07. Keywords next to "$ref", which older
+// dialects ignore, are removed. The returned issues list what Migrate could
+// not carry over exactly, such as "$recursiveRef" or a custom meta-schema.
+//
+// Migrate fails with ErrUnsupportedMigration when target is not newer than
+// Draft-04 or the document uses Draft-03.
+func Migrate(schemaJSON []byte, target Dialect) ([]byte, []MigrationIssue, error) {
+	if dialectRank(target) <= dialectRank(Draft4) {
+		return nil, nil, fmt.Errorf("%w: target %s", ErrUnsupportedMigration, target)
+	}
+	document, err := decodeOrderedJSON(schemaJSON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+	}
+
+	m := &migrator{target: target}
+	if err := m.migrate(document, "", ""); err != nil {
+		return nil, nil, err
+	}
+	if root, ok := document.(*orderedObject); ok {
+		fixReferences(root)
+		if _, declared := root.get("$schema"); !declared {
+			root.insert(0, "$schema", jsontext.Value(strconv.Quote(string(target))))
+		}
+	}
+
+	var buf bytes.Buffer
+	var options []jsontext.Options
+	multiline := bytes.Contains(bytes.TrimSpace(schemaJSON), []byte("\n"))
+	if multiline {
+		options = append(options, jsontext.WithIndent("  "))
+	}
+	encoder := jsontext.NewEncoder(&buf, options...)
+	if err := encodeOrderedJSON(encoder, document); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+	}
+	output := buf.Bytes()
+	if !multiline {
+		output = bytes.TrimSuffix(output, []byte("\n"))
+	}
+	return output, m.issues, nil
+}
+
+// dialectRank orders dialects by publication, zero for unknown ones.
+func dialectRank(dialect Dialect) int {
+	return slices.Index([]Dialect{Draft3, Draft4, Draft6, Draft7, Draft201909, Draft202012}, dialect) + 1
+}
+
+// migrator rewrites a document for a target dialect.
+type migrator struct {
+	target Dialect
+	issues []MigrationIssue
+}
+
+func (m *migrator) report(location, keyword, format string, args ...any) {
+	m.issues = append(m.issues, MigrationIssue{Location: location, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+}
+
+// migrate rewrites the schema value at location, written in source, then
+// its subschemas. An empty source is an undeclared older dialect.
+func (m *migrator) migrate(value any, location string, source Dialect) error {
+	object, ok := value.(*orderedObject)
+	if !ok {
+		return nil
+	}
+	if raw, ok := object.get("$schema"); ok {
+		if uri, isString := jsonString(raw); isString {
+			if dialect := dialectFromSchemaURI(uri, ""); dialect != "" {
+				source = dialect
+				object.set("$schema", jsontext.Value(strconv.Quote(string(m.target))))
+			} else {
+				m.report(location, "$schema", "custom meta-schema %q is kept; update it for %s", uri, dialectNames[m.target])
+			}
+		}
+	}
+	if source == Draft3 {
+		return fmt.Errorf("%w: Draft-03 schema at #%s", ErrUnsupportedMigration, location)
+	}
+
+	// Subschemas are migrated under their source names, so locations match
+	// the source document.
+	keywordLocation := func(keyword string) string {
+		return location + "/" + pointerEscaper.Replace(keyword)
+	}
+	for _, member := range slices.Clone(object.members) {
+		var err error
+		switch {
+		case slices.Contains(schemaKeywords, member.name):
+			if _, isArray := member.value.([]any); !isArray {
+				err = m.migrate(member.value, keywordLocation(member.name), source)
+			}
+		case slices.Contains(schemaMapKeywords, member.name):
+			if members, isObject := member.value.(*orderedObject); isObject {
+				for _, child := range members.members {
+					if err = m.migrate(child.value, keywordLocation(member.name)+"/"+pointerEscaper.Replace(child.name), source); err != nil {
+						break
+					}
+				}
+			}
+		}
+		if err == nil && slices.Contains(schemaArrayKeywords, member.name) {
+			if elements, isArray := member.value.([]any); isArray {
+				for i, element := range elements {
+					if err = m.migrate(element, fmt.Sprintf("%s/%d", keywordLocation(member.name), i), source); err != nil {
+						break
+					}
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	rank := dialectRank(source)
+	if source == "" {
+		rank = dialectRank(Draft4)
+	}
+	if rank >= dialectRank(m.target) {
+		return nil
+	}
+	if rank <= dialectRank(Draft4) {
+		m.migrateDraft4(object)
+	}
+	if rank <= dialectRank(Draft7) && dialectRank(m.target) >= dialectRank(Draft201909) {
+		m.migrateDraft7(object, location)
+	}
+	if rank <= dialectRank(Draft201909) && m.target == Draft202012 {
+		m.migrateDraft201909(object, location)
+	}
+	return nil
+}
+
+// migrateDraft4 rewrites "id" and the boolean exclusive bounds.
+func (m *migrator) migrateDraft4(object *orderedObject) {
+	if raw, ok := object.get("id"); ok {
+		if id, isString := jsonString(raw); isString {
+			base, fragment, _ := strings.Cut(id, "#")
+			switch {
+			case fragment != "" && dialectRank(m.target) >= dialectRank(Draft201909):
+				index := object.index("id")
+				object.remove("id")
+				if base != "" {
+					object.insert(index, "$id", jsontext.Value(strconv.Quote(base)))
+					index++
+				}
+				object.insert(index, "$anchor", jsontext.Value(strconv.Quote(fragment)))
+			case fragment == "" && base != id:
+				object.replace("id", "$id", jsontext.Value(strconv.Quote(base)))
+			default:
+				object.replace("id", "$id", raw)
+			}
+		}
+	}
+
+	for _, bound := range []struct{ exclusive, inclusive string }{
+		{"exclusiveMinimum", "minimum"},
+		{"exclusiveMaximum", "maximum"},
+	} {
+		raw, _ := object.get(bound.exclusive)
+		value, isScalar := raw.(jsontext.Value)
+		if !isScalar || !isJSONTrue(value) && !isJSONFalse(value) {
+			continue
+		}
+		isTrue := isJSONTrue(value)
+		limit, hasLimit := object.get(bound.inclusive)
+		if isTrue && hasLimit {
+			object.set(bound.exclusive, limit)
+			object.remove(bound.inclusive)
+		} else {
+			object.remove(bound.exclusive)
+		}
+	}
+}
+
+// migrateDraft7 rewrites "definitions" and "dependencies", and removes the
+// keywords next to "$ref" that Draft-07 and earlier ignore.
+func (m *migrator) migrateDraft7(object *orderedObject, location string) {
+	if _, hasRef := object.get("$ref"); hasRef {
+		var ignored []string
+		for _, member := range slices.Clone(object.members) {
+			switch member.name {
+			case "$ref", "$schema", "$comment", "title", "description", "default", "examples", "definitions":
+			default:
+				object.remove(member.name)
+				ignored = append(ignored, member.name)
+			}
+		}
+		if len(ignored) > 0 {
+			m.report(location, "$ref", "keywords next to $ref were ignored and are removed: %s", strings.Join(ignored, ", "))
+		}
+	}
+
+	if raw, ok := object.get("definitions"); ok {
+		if _, hasDefs := object.get("$defs"); hasDefs {
+			m.report(location, "definitions", "definitions is kept next to $defs")
+		} else {
+			object.replace("definitions", "$defs", raw)
+		}
+	}
+
+	if raw, ok := object.get("dependencies"); ok {
+		dependencies, isObject := raw.(*orderedObject)
+		if !isObject {
+			return
+		}
+		required, schemas := &orderedObject{}, &orderedObject{}
+		for _, member := range dependencies.members {
+			if _, isArray := member.value.([]any); isArray {
+				required.members = append(required.members, member)
+			} else {
+				schemas.members = append(schemas.members, member)
+			}
+		}
+		index := object.index("dependencies")
+		object.remove("dependencies")
+		for _, split := range []struct {
+			name    string
+			members *orderedObject
+		}{{"dependentRequired", required}, {"dependentSchemas", schemas}} {
+			if len(split.members.members) > 0 {
+				object.insert(index, split.name, split.members)
+				index++
+			}
+		}
+		object.rename("dependencies", "dependentSchemas")
+	}
+}
+
+// migrateDraft201909 rewrites the tuple form of "items" and reports the
+// recursive references, which have no exact Draft 2020-12 equivalent.
+func (m *migrator) migrateDraft201909(object *orderedObject, location string) {
+	if items, ok := object.get("items"); ok {
+		if _, isArray := items.([]any); isArray {
+			additional, hasAdditional := object.get("additionalItems")
+			object.replace("items", "prefixItems", items)
+			if hasAdditional {
+				object.replace("additionalItems", "items", additional)
+			}
+		}
+	}
+	if _, ok := object.get("additionalItems"); ok {
+		object.remove("additionalItems")
+		m.report(location, "additionalItems", "additionalItems without array items has no effect and is removed")
+	}
+	for _, keyword := range []string{"$recursiveRef", "$recursiveAnchor"} {
+		if _, ok := object.get(keyword); ok {
+			m.report(location, keyword, "%s has no exact Draft 2020-12 equivalent and is kept; use $dynamicRef and $dynamicAnchor", keyword)
+		}
+	}
+}
+
+// fixReferences rewrites the "$ref" pointers of the migrated document root
+// to follow renamed keywords.
+func fixReferences(root *orderedObject) {
+	resources := make(map[string]*orderedObject)
+	var collect func(value any, base string)
+	collect = func(value any, base string) {
+		object, ok := value.(*orderedObject)
+		if !ok {
+			return
+		}
+		if id, ok := resourceID(object, base); ok {
+			base = id
+			resources[id] = object
+		}
+		forEachOrderedSubschema(object, func(child any) { collect(child, base) })
+	}
+	collect(root, "")
+
+	var visit func(value any, resource *orderedObject, base string)
+	visit = func(value any, resource *orderedObject, base string) {
+		object, ok := value.(*orderedObject)
+		if !ok {
+			return
+		}
+		if id, ok := resourceID(object, base); ok {
+			base, resource = id, object
+		}
+		if raw, ok := object.get("$ref"); ok {
+			if ref, isString := jsonString(raw); isString {
+				if fixed := fixReference(ref, resource, base, resources); fixed != ref {
+					object.set("$ref", jsontext.Value(strconv.Quote(fixed)))
+				}
+			}
+		}
+		forEachOrderedSubschema(object, func(child any) { visit(child, resource, base) })
+	}
+	visit(root, root, "")
+}
+
+// resourceID returns the URI a migrated schema object with "$id" identifies,
+// resolved against base.
+func resourceID(object *orderedObject, base string) (string, bool) {
+	raw, ok := object.get("$id")
+	if !ok {
+		return "", false
+	}
+	id, ok := jsonString(raw)
+	if !ok || strings.HasPrefix(id, "#") {
+		return "", false // A plain-name fragment is an anchor.
+	}
+	id, _, _ = strings.Cut(resolveRelativeURI(base, id), "#")
+	return id, true
+}
+
+// fixReference returns ref with its JSON Pointer fragment following the
+// keywords renamed in the resource it points into.
+func fixReference(ref string, resource *orderedObject, base string, resources map[string]*orderedObject) string {
+	uri, fragment, found := strings.Cut(ref, "#")
+	if !found || !strings.HasPrefix(fragment, "/") {
+		return ref
+	}
+	if uri != "" {
+		target, _, _ := strings.Cut(resolveRelativeURI(base, uri), "#")
+		if resources[target] == nil {
+			return ref
+		}
+		resource = resources[target]
+	}
+
+	segments := strings.Split(fragment, "/")[1:]
+	var current any = resource
+	for i, segment := range segments {
+		token := segment
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			token = unescaped
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch node := current.(type) {
+		case *orderedObject:
+			if renamed, ok := node.renamed[token]; ok {
+				token = renamed
+				segments[i] = pointerEscaper.Replace(renamed)
+			}
+			child, ok := node.get(token)
+			if !ok {
+				return ref
+			}
+			current = child
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return ref
+			}
+			current = node[index]
+		default:
+			return ref
+		}
+	}
+	return uri + "#/" + strings.Join(segments, "/")
+}
+
+// forEachOrderedSubschema calls fn for the subschemas of a migrated schema
+// object.
+func forEachOrderedSubschema(object *orderedObject, fn func(any)) {
+	for _, member := range object.members {
+		switch {
+		case slices.Contains(schemaMapKeywords, member.name):
+			if members, ok := member.value.(*orderedObject); ok {
+				for _, child := range members.members {
+					fn(child.value)
+				}
+			}
+		case slices.Contains(schemaArrayKeywords, member.name):
+			if elements, ok := member.value.([]any); ok {
+				for _, element := range elements {
+					fn(element)
+				}
+				continue
+			}
+			fn(member.value)
+		case slices.Contains(schemaKeywords, member.name):
+			fn(member.value)
+		}
+	}
+}
+
+func jsonString(value any) (string, bool) {
+	raw, ok := value.(jsontext.Value)
+	if !ok || raw.Kind() != '"' {
+		return "", false
+	}
+	var s string
+	if err := unmarshalJSON(raw, &s); err != nil {
+		return "", false
+	}
+	return s, true
+}
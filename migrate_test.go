@@ -0,0 +1,124 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		target   Dialect
+		expected string
+		issues   []MigrationIssue
+	}{
+		{
+			name:     "draft-04 keywords keep their place",
+			source:   `{"$schema": "http://json-schema.org/draft-04/schema#", "id": "https://example.com/a.json", "type": "number", "minimum": 1, "exclusiveMinimum": true, "maximum": 9, "exclusiveMaximum": false}`,
+			target:   Draft202012,
+			expected: `{"$schema":"https://json-schema.org/draft/2020-12/schema","$id":"https://example.com/a.json","type":"number","exclusiveMinimum":1,"maximum":9}`,
+		},
+		{
+			name:     "definitions, dependencies and tuples",
+			source:   `{"items": [{"$ref": "#/definitions/a"}], "additionalItems": {"$ref": "#/items/0"}, "dependencies": {"x": ["y"], "y": {"$ref": "#/dependencies/x"}}, "definitions": {"a": {"type": "string"}}}`,
+			target:   Draft202012,
+			expected: `{"$schema":"https://json-schema.org/draft/2020-12/schema","prefixItems":[{"$ref":"#/$defs/a"}],"items":{"$ref":"#/prefixItems/0"},"dependentRequired":{"x":["y"]},"dependentSchemas":{"y":{"$ref":"#/dependencies/x"}},"$defs":{"a":{"type":"string"}}}`,
+		},
+		{
+			name:     "draft 2019-09 keeps tuples",
+			source:   `{"$schema": "http://json-schema.org/draft-07/schema#", "items": [{}], "additionalItems": false, "definitions": {}}`,
+			target:   Draft201909,
+			expected: `{"$schema":"https://json-schema.org/draft/2019-09/schema","items":[{}],"additionalItems":false,"$defs":{}}`,
+		},
+		{
+			name:     "draft-07 keeps definitions",
+			source:   `{"$schema": "http://json-schema.org/draft-04/schema#", "id": "#root", "definitions": {"a": {"id": "https://example.com/a.json"}}}`,
+			target:   Draft7,
+			expected: `{"$schema":"http://json-schema.org/draft-07/schema#","$id":"#root","definitions":{"a":{"$id":"https://example.com/a.json"}}}`,
+		},
+		{
+			name:     "references into embedded resources",
+			source:   `{"id": "https://example.com/root.json", "properties": {"a": {"$ref": "item.json#/definitions/b"}, "c": {"$ref": "other.json#/definitions/d"}}, "definitions": {"item": {"id": "item.json", "definitions": {"b": {"id": "#b"}}}}}`,
+			target:   Draft202012,
+			expected: `{"$schema":"https://json-schema.org/draft/2020-12/schema","$id":"https://example.com/root.json","properties":{"a":{"$ref":"item.json#/$defs/b"},"c":{"$ref":"other.json#/definitions/d"}},"$defs":{"item":{"$id":"item.json","$defs":{"b":{"$anchor":"b"}}}}}`,
+		},
+		{
+			name:     "constructs without exact equivalent",
+			source:   `{"$schema": "https://json-schema.org/draft/2019-09/schema", "$recursiveAnchor": true, "properties": {"a": {"$schema": "https://example.com/meta.json", "$ref": "#", "type": "string"}}, "additionalItems": false}`,
+			target:   Draft202012,
+			expected: `{"$schema":"https://json-schema.org/draft/2020-12/schema","$recursiveAnchor":true,"properties":{"a":{"$schema":"https://example.com/meta.json","$ref":"#","type":"string"}}}`,
+			issues: []MigrationIssue{
+				{Location: "/properties/a", Keyword: "$schema", Message: `custom meta-schema "https://example.com/meta.json" is kept; update it for Draft 2020-12`},
+				{Location: "", Keyword: "additionalItems", Message: "additionalItems without array items has no effect and is removed"},
+				{Location: "", Keyword: "$recursiveAnchor", Message: "$recursiveAnchor has no exact Draft 2020-12 equivalent and is kept; use $dynamicRef and $dynamicAnchor"},
+			},
+		},
+		{
+			name:     "siblings of $ref",
+			source:   `{"$ref": "#/definitions/a", "title": "A", "type": "object", "minProperties": 1, "definitions": {"a": {}}}`,
+			target:   Draft202012,
+			expected: `{"$schema":"https://json-schema.org/draft/2020-12/schema","$ref":"#/$defs/a","title":"A","$defs":{"a":{}}}`,
+			issues: []MigrationIssue{
+				{Location: "", Keyword: "$ref", Message: "keywords next to $ref were ignored and are removed: type, minProperties"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrated, issues, err := Migrate([]byte(tt.source), tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(migrated))
+			assert.Equal(t, tt.issues, issues)
+		})
+	}
+}
+
+func TestMigrateKeepsValidation(t *testing.T) {
+	source := `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {
+			"total": {"type": "number", "minimum": 0, "exclusiveMinimum": true},
+			"lines": {"type": "array", "items": [{"$ref": "#/definitions/sku"}], "additionalItems": false}
+		},
+		"dependencies": {"total": ["lines"]},
+		"definitions": {"sku": {"type": "string", "pattern": "^[A-Z]+$"}}
+	}`
+	migrated, issues, err := Migrate([]byte(source), Draft202012)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	assert.Contains(t, string(migrated), "\n  \"properties\": {", "multi-line documents stay indented")
+
+	compiler := NewCompiler()
+	before, err := compiler.Compile([]byte(source))
+	require.NoError(t, err)
+	after, err := compiler.Compile(migrated)
+	require.NoError(t, err)
+	assert.Equal(t, Draft202012, after.Dialect())
+
+	for _, instance := range []any{
+		map[string]any{"total": 1, "lines": []any{"ABC"}},
+		map[string]any{"total": 0, "lines": []any{"ABC"}},
+		map[string]any{"total": 1},
+		map[string]any{"lines": []any{"ABC", "DEF"}},
+		map[string]any{"lines": []any{"abc"}},
+	} {
+		assert.Equal(t, before.Validate(instance).IsValid(), after.Validate(instance).IsValid(), "%v", instance)
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	_, _, err := Migrate([]byte(`{}`), Draft4)
+	require.ErrorIs(t, err, ErrUnsupportedMigration)
+	_, _, err = Migrate([]byte(`{"$schema": "http://json-schema.org/draft-03/schema#"}`), Draft202012)
+	require.ErrorIs(t, err, ErrUnsupportedMigration)
+	_, _, err = Migrate([]byte(`{"type": }`), Draft202012)
+	require.ErrorIs(t, err, ErrJSONUnmarshal)
+
+	migrated, _, err := Migrate([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "items": [{}]}`), Draft202012)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"$schema": "https://json-schema.org/draft/2020-12/schema", "items": [{}]}`, string(migrated), "schemas of the target dialect are left alone")
+}
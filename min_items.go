@@ -14,7 +14,7 @@ func evaluateMinItems(schema *Schema, array []any) *EvaluationError {
 	if schema.MinItems != nil {
 		if float64(len(array)) < *schema.MinItems {
 			// If the array size is less than the minimum required, construct and return an error.
-			return NewEvaluationError("minItems", "items_too_short", "Value should have at least {min_items} items", map[string]any{
+			return newError("minItems", CodeItemsTooShort, map[string]any{
 				"min_items": *schema.MinItems,
 				"count":     len(array),
 			})
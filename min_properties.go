@@ -18,7 +18,7 @@ func evaluateMinProperties(schema *Schema, object map[string]any) *EvaluationErr
 
 	actualCount := float64(len(object))
 	if actualCount < minProperties {
-		return NewEvaluationError("minProperties", "too_few_properties", "Value should have at least {min_properties} properties", map[string]any{
+		return newError("minProperties", CodeTooFewProperties, map[string]any{
 			"min_properties": minProperties,
 		})
 	}
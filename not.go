@@ -22,7 +22,7 @@ func evaluateNot(schema *Schema, instance any, _ map[string]bool, _ map[int]bool
 			SetSchemaLocation(schema.SchemaLocation("/not"))
 
 		if result.IsValid() {
-			return result, NewEvaluationError("not", "not_schema_mismatch", "Value should not match the not schema")
+			return result, newError("not", CodeNotSchemaMismatch)
 		}
 	}
 
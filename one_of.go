@@ -55,10 +55,10 @@ func evaluateOneOf(
 	}
 
 	if len(validIndexes) > 1 {
-		return results, NewEvaluationError("oneOf", "one_of_multiple_matches", "Value should match exactly one schema but matches multiple at indexes {matches}", map[string]any{
+		return results, newError("oneOf", CodeOneOfMultipleMatches, map[string]any{
 			"matches": strings.Join(validIndexes, ", "),
 		})
 	}
 	// If no conditions are met, return error
-	return results, NewEvaluationError("oneOf", "one_of_item_mismatch", "Value does not match the oneOf schema")
+	return results, newError("oneOf", CodeOneOfItemMismatch)
 }
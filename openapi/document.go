@@ -0,0 +1,412 @@
+// Package openapi validates HTTP requests and responses against an OpenAPI
+// 3.1 description. Load compiles the schemas of the description with
+// jsonschema, and Document.ValidateRequest and Document.ValidateResponse
+// match the operation by path template and method, then check its
+// parameters and bodies.
+package openapi
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// DefaultBaseURI is the base URI of a document loaded without
+// LoadOptions.BaseURI. It is not hierarchical, so relative references to
+// other documents fail to resolve instead of being fetched.
+const DefaultBaseURI = "urn:x-openapi:document"
+
+// openAPIDialectPrefix starts the URIs of the OpenAPI 3.1 base dialect,
+// Draft 2020-12 with annotation-only keywords such as "discriminator".
+const openAPIDialectPrefix = "https://spec.openapis.org/oas/3.1/dialect/"
+
+// LoadOptions configures Load.
+type LoadOptions struct {
+	// BaseURI is the URI the document was retrieved from. Relative
+	// references in the document resolve against it. Defaults to
+	// DefaultBaseURI.
+	BaseURI string
+	// Compiler supplies the formats, loaders and settings schemas are
+	// compiled with. Load compiles on a compiler derived from it, so the
+	// document's schemas are not cached on it. Defaults to
+	// jsonschema.NewCompiler().
+	Compiler *jsonschema.Compiler
+}
+
+// Document is a loaded OpenAPI 3.1 description with its schemas compiled.
+// It is safe for concurrent use.
+type Document struct {
+	// Version is the "openapi" version of the document, such as "3.1.0".
+	Version string
+
+	baseURI  string
+	document map[string]any
+	schemas  map[string]*jsonschema.Schema // Schema Objects by location in the document.
+	prefixes []string                      // Path prefixes of the server URLs.
+	routes   []*route
+}
+
+// Load reads an OpenAPI 3.1 description in JSON or YAML and compiles every
+// Schema Object in it. Schemas without "$schema" use the document's
+// "jsonSchemaDialect", which defaults to Draft 2020-12, and references
+// between them, such as "#/components/schemas/Pet", resolve within the
+// document. Load fails with ErrReferenceResolution when a reference cannot
+// be resolved.
+func Load(data []byte, opts LoadOptions) (*Document, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		converted, err := jsonschema.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+		}
+		data = converted
+	}
+	value, err := decodeJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+	document, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an object", ErrInvalidDocument)
+	}
+	version, _ := document["openapi"].(string)
+	if !strings.HasPrefix(version, "3.1.") {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedVersion, version)
+	}
+
+	baseURI, _, _ := strings.Cut(cmp.Or(opts.BaseURI, DefaultBaseURI), "#")
+	d := &Document{Version: version, baseURI: baseURI, document: document}
+	if err := d.compileSchemas(opts.Compiler); err != nil {
+		return nil, err
+	}
+	if err := d.buildRoutes(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Schema returns the compiled schema of components/schemas/name, or nil.
+func (d *Document) Schema(name string) *jsonschema.Schema {
+	return d.schemas["/components/schemas/"+escapePointerToken(name)]
+}
+
+// compileSchemas compiles the Schema Objects of the document as the "$defs"
+// of one schema identified by the document URI, keyed by their location.
+// References into the document are rewritten to point at those "$defs".
+func (d *Document) compileSchemas(compiler *jsonschema.Compiler) error {
+	locations := make(map[string]any)
+	collectSchemas(d.document, locations)
+
+	defs := make(map[string]any, len(locations))
+	for location, schema := range locations {
+		d.rewriteReferences(schema, d.baseURI, locations)
+		defs[location] = schema
+	}
+	dialect, _ := d.document["jsonSchemaDialect"].(string)
+	if dialect == "" || strings.HasPrefix(dialect, openAPIDialectPrefix) {
+		dialect = string(jsonschema.Draft202012)
+	}
+	data, err := json.Marshal(map[string]any{"$schema": dialect, "$id": d.baseURI, "$defs": defs})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+
+	if compiler == nil {
+		compiler = jsonschema.NewCompiler()
+	}
+	root, err := compiler.Derive().Compile(data)
+	if err != nil {
+		return fmt.Errorf("compiling schemas: %w", err)
+	}
+	if unresolved := root.UnresolvedReferenceURIs(); len(unresolved) > 0 {
+		return fmt.Errorf("%w: %s", ErrReferenceResolution, strings.Join(unresolved, ", "))
+	}
+
+	d.schemas = make(map[string]*jsonschema.Schema, len(locations))
+	for location := range locations {
+		d.schemas[location] = root.Defs[location]
+	}
+	return nil
+}
+
+// Keywords whose values hold subschemas, by shape.
+var (
+	subschemaKeywords = []string{
+		"not", "if", "then", "else", "items", "additionalItems", "contains",
+		"additionalProperties", "propertyNames", "unevaluatedItems",
+		"unevaluatedProperties", "contentSchema",
+	}
+	subschemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"}
+	subschemaMapKeywords   = []string{
+		"properties", "patternProperties", "$defs", "definitions",
+		"dependentSchemas", "dependencies",
+	}
+)
+
+// rewriteReferences points the "$ref" keywords of schema and its subschemas
+// that target a Schema Object of the document at its compiled location.
+func (d *Document) rewriteReferences(schema any, base string, locations map[string]any) {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return
+	}
+	if id, ok := object["$id"].(string); ok {
+		base, _, _ = strings.Cut(resolveURI(base, id), "#")
+	}
+	if ref, ok := object["$ref"].(string); ok {
+		object["$ref"] = d.documentReference(resolveURI(base, ref), ref, locations)
+	}
+
+	for keyword, value := range object {
+		switch {
+		case slices.Contains(subschemaKeywords, keyword):
+			d.rewriteReferences(value, base, locations)
+		case slices.Contains(subschemaMapKeywords, keyword):
+			if members, ok := value.(map[string]any); ok {
+				for _, member := range members {
+					d.rewriteReferences(member, base, locations)
+				}
+			}
+		}
+		if slices.Contains(subschemaArrayKeywords, keyword) {
+			if elements, ok := value.([]any); ok {
+				for _, element := range elements {
+					d.rewriteReferences(element, base, locations)
+				}
+			}
+		}
+	}
+}
+
+// documentReference returns the compiled location of the resolved reference
+// uri when it points into a Schema Object of the document, and ref
+// otherwise.
+func (d *Document) documentReference(uri, ref string, locations map[string]any) string {
+	target, fragment, _ := strings.Cut(uri, "#")
+	if target != d.baseURI || !strings.HasPrefix(fragment, "/") {
+		return ref
+	}
+	pointer, err := url.PathUnescape(fragment)
+	if err != nil {
+		return ref
+	}
+
+	var location string
+	for candidate := range locations {
+		if (pointer == candidate || strings.HasPrefix(pointer, candidate+"/")) && len(candidate) > len(location) {
+			location = candidate
+		}
+	}
+	if location == "" {
+		return ref
+	}
+	rest := strings.Split(pointer[len(location):], "/")
+	for i, token := range rest {
+		rest[i] = url.PathEscape(token)
+	}
+	return d.baseURI + "#/$defs/" + url.PathEscape(escapePointerToken(location)) + strings.Join(rest, "/")
+}
+
+// resolve follows the Reference Objects starting at value, at location, and
+// returns the object they lead to and its location.
+func (d *Document) resolve(value any, location string) (map[string]any, string, error) {
+	for range 32 {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, "", fmt.Errorf("%w: #%s is not an object", ErrInvalidDocument, location)
+		}
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object, location, nil
+		}
+		target, fragment, _ := strings.Cut(resolveURI(d.baseURI, ref), "#")
+		pointer, err := url.PathUnescape(fragment)
+		if target != d.baseURI || err != nil || (pointer != "" && !strings.HasPrefix(pointer, "/")) {
+			return nil, "", fmt.Errorf("%w: %s at #%s", ErrReferenceResolution, ref, location)
+		}
+		if value, ok = lookupPointer(d.document, pointer); !ok {
+			return nil, "", fmt.Errorf("%w: %s at #%s", ErrReferenceResolution, ref, location)
+		}
+		location = pointer
+	}
+	return nil, "", fmt.Errorf("%w: too many references at #%s", ErrReferenceResolution, location)
+}
+
+// collectSchemas records the Schema Objects of document by location. Schemas
+// behind Reference Objects are recorded where they are defined.
+func collectSchemas(document map[string]any, schemas map[string]any) {
+	c := schemaCollector(schemas)
+	components, _ := document["components"].(map[string]any)
+	eachMember(components["schemas"], "/components/schemas", func(schema any, location string) {
+		schemas[location] = schema
+	})
+	eachMember(components["parameters"], "/components/parameters", c.parameter)
+	eachMember(components["headers"], "/components/headers", c.parameter)
+	eachMember(components["requestBodies"], "/components/requestBodies", c.content)
+	eachMember(components["responses"], "/components/responses", c.response)
+	eachMember(components["pathItems"], "/components/pathItems", c.pathItem)
+	eachMember(document["paths"], "/paths", c.pathItem)
+	eachMember(document["webhooks"], "/webhooks", c.pathItem)
+}
+
+// schemaCollector walks the objects of an OpenAPI document holding schemas.
+type schemaCollector map[string]any
+
+// httpMethods are the operations of a Path Item Object.
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+func (c schemaCollector) pathItem(value any, location string) {
+	item := unreferenced(value)
+	if item == nil {
+		return
+	}
+	eachElement(item["parameters"], location+"/parameters", c.parameter)
+	for _, method := range httpMethods {
+		operation := unreferenced(item[method])
+		if operation == nil {
+			continue
+		}
+		operationLocation := location + "/" + method
+		eachElement(operation["parameters"], operationLocation+"/parameters", c.parameter)
+		c.content(operation["requestBody"], operationLocation+"/requestBody")
+		eachMember(operation["responses"], operationLocation+"/responses", c.response)
+		eachMember(operation["callbacks"], operationLocation+"/callbacks", func(callback any, location string) {
+			eachMember(callback, location, c.pathItem)
+		})
+	}
+}
+
+// parameter records the schema of a Parameter or Header Object.
+func (c schemaCollector) parameter(value any, location string) {
+	parameter := unreferenced(value)
+	if parameter == nil {
+		return
+	}
+	if schema, ok := parameter["schema"]; ok {
+		c[location+"/schema"] = schema
+	}
+	c.content(parameter, location)
+}
+
+func (c schemaCollector) response(value any, location string) {
+	response := unreferenced(value)
+	if response == nil {
+		return
+	}
+	eachMember(response["headers"], location+"/headers", c.parameter)
+	c.content(response, location)
+}
+
+// content records the schemas of the "content" of value.
+func (c schemaCollector) content(value any, location string) {
+	holder := unreferenced(value)
+	if holder == nil {
+		return
+	}
+	eachMember(holder["content"], location+"/content", func(mediaType any, location string) {
+		if object, ok := mediaType.(map[string]any); ok {
+			if schema, ok := object["schema"]; ok {
+				c[location+"/schema"] = schema
+			}
+		}
+	})
+}
+
+// unreferenced returns value when it is an object other than a Reference
+// Object.
+func unreferenced(value any) map[string]any {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if _, isReference := object["$ref"]; isReference {
+		return nil
+	}
+	return object
+}
+
+func eachMember(value any, location string, fn func(any, string)) {
+	object, _ := value.(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(object)) {
+		fn(object[name], location+"/"+escapePointerToken(name))
+	}
+}
+
+func eachElement(value any, location string, fn func(any, string)) {
+	elements, _ := value.([]any)
+	for i, element := range elements {
+		fn(element, fmt.Sprintf("%s/%d", location, i))
+	}
+}
+
+func lookupPointer(document any, pointer string) (any, bool) {
+	if pointer == "" {
+		return document, true
+	}
+	value := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch container := value.(type) {
+		case map[string]any:
+			var ok bool
+			if value, ok = container[token]; !ok {
+				return nil, false
+			}
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, false
+			}
+			value = container[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointerToken(token string) string {
+	return pointerEscaper.Replace(token)
+}
+
+// resolveURI resolves ref against base. Fragment-only references keep a
+// base that is not hierarchical, such as DefaultBaseURI.
+func resolveURI(base, ref string) string {
+	if strings.HasPrefix(ref, "#") {
+		base, _, _ = strings.Cut(base, "#")
+		return base + ref
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers exact.
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return value, nil
+}
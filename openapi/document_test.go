@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kaptinlin/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore = `
+openapi: 3.1.0
+info: {title: Petstore, version: "1"}
+servers:
+  - url: https://api.example.com/{version}
+    variables:
+      version: {default: v1}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+        - {name: tags, in: query, schema: {type: array, items: {type: string}}}
+        - {name: ids, in: query, explode: false, schema: {type: array, items: {type: integer}}}
+        - {name: sizes, in: query, style: pipeDelimited, schema: {type: array, items: {enum: [s, m, l]}}}
+        - name: filter
+          in: query
+          style: deepObject
+          schema:
+            type: object
+            properties:
+              age: {type: integer}
+            additionalProperties: false
+        - $ref: '#/components/parameters/RequestID'
+      responses:
+        "200":
+          description: The pets.
+          headers:
+            X-Total: {schema: {type: integer}, required: true}
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+        default:
+          $ref: '#/components/responses/Error'
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                age: {type: integer}
+                tags: {type: array, items: {type: string}}
+          text/*:
+            schema: {type: string, maxLength: 5}
+      responses:
+        "201": {description: Created.}
+  /pets/mine:
+    get:
+      responses:
+        "200": {description: Mine.}
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: integer, minimum: 1}}
+    get:
+      responses:
+        2XX: {description: The pet.}
+    delete:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string, pattern: "^[a-z]+$"}}
+      responses:
+        "204": {description: Deleted.}
+  /points/{point}:
+    get:
+      parameters:
+        - {name: point, in: path, required: true, style: matrix, explode: true, schema: {$ref: '#/components/schemas/Point'}}
+        - {name: X-Coords, in: header, schema: {type: array, items: {type: number}}}
+        - name: where
+          in: query
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Point'}
+      responses:
+        "200": {description: The point.}
+  /labels/{labels}:
+    get:
+      parameters:
+        - {name: labels, in: path, required: true, style: label, schema: {type: array, items: {type: string}, minItems: 2}}
+      responses:
+        "200": {description: The labels.}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        age: {type: integer, minimum: 0}
+        owner: {$ref: '#/components/schemas/Owner/properties/name'}
+    Owner:
+      type: object
+      properties:
+        name: {type: string, minLength: 1}
+    Point:
+      type: object
+      required: [x, y]
+      properties:
+        x: {type: integer}
+        y: {type: integer}
+  parameters:
+    RequestID:
+      name: X-Request-ID
+      in: header
+      required: true
+      schema: {type: string, format: uuid}
+  responses:
+    Error:
+      description: An error.
+      content:
+        application/problem+json:
+          schema:
+            type: object
+            required: [title]
+`
+
+func loadPetstore(t *testing.T) *Document {
+	t.Helper()
+	document, err := Load([]byte(petstore), LoadOptions{
+		Compiler: jsonschema.NewCompiler().SetAssertFormat(true),
+	})
+	require.NoError(t, err)
+	return document
+}
+
+func TestLoad(t *testing.T) {
+	document := loadPetstore(t)
+	assert.Equal(t, "3.1.0", document.Version)
+
+	pet := document.Schema("Pet")
+	require.NotNil(t, pet)
+	assert.True(t, pet.Validate(map[string]any{"name": "Rex", "owner": "Ann"}).IsValid())
+	assert.False(t, pet.Validate(map[string]any{"name": "Rex", "owner": ""}).IsValid(), "references resolve into other components")
+	assert.False(t, pet.Validate(map[string]any{"age": 1}).IsValid())
+	assert.Nil(t, document.Schema("Missing"))
+}
+
+func TestLoadJSON(t *testing.T) {
+	document, err := Load([]byte(`{
+		"openapi": "3.1.1",
+		"info": {"title": "JSON", "version": "1"},
+		"components": {"schemas": {
+			"a/b": {"type": "string"},
+			"C": {"$ref": "#/components/schemas/a~1b"}
+		}}
+	}`), LoadOptions{})
+	require.NoError(t, err)
+	assert.True(t, document.Schema("C").Validate("x").IsValid())
+	assert.False(t, document.Schema("C").Validate(1).IsValid())
+}
+
+func TestLoadBaseURI(t *testing.T) {
+	source := `
+openapi: 3.1.0
+info: {title: Base, version: "1"}
+components:
+  schemas:
+    Nested:
+      $id: schemas/nested.json
+      $defs:
+        name: {type: string}
+      properties:
+        name: {$ref: '#/$defs/name'}
+        other: {$ref: '../openapi.yaml#/components/schemas/Other'}
+    Other: {type: integer}
+`
+	document, err := Load([]byte(source), LoadOptions{BaseURI: "https://example.com/api/openapi.yaml"})
+	require.NoError(t, err)
+	nested := document.Schema("Nested")
+	assert.True(t, nested.Validate(map[string]any{"name": "a", "other": 1}).IsValid())
+	assert.False(t, nested.Validate(map[string]any{"name": 1}).IsValid(), "references resolve against the schema $id")
+	assert.False(t, nested.Validate(map[string]any{"other": "x"}).IsValid(), "relative references resolve against the base URI")
+}
+
+func TestLoadDialect(t *testing.T) {
+	source := func(dialect string) []byte {
+		return []byte(`{
+			"openapi": "3.1.0",
+			"info": {"title": "Dialect", "version": "1"},
+			"jsonSchemaDialect": "` + dialect + `",
+			"components": {"schemas": {"Tuple": {"items": [{"type": "string"}]}}}
+		}`)
+	}
+	document, err := Load(source("http://json-schema.org/draft-07/schema#"), LoadOptions{})
+	require.NoError(t, err)
+	assert.False(t, document.Schema("Tuple").Validate([]any{1}).IsValid(), "Draft-07 array items are a tuple")
+
+	document, err = Load(source("https://spec.openapis.org/oas/3.1/dialect/base"), LoadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, jsonschema.Draft202012, document.Schema("Tuple").Dialect())
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    error
+	}{
+		{"not an object", `[]`, ErrInvalidDocument},
+		{"invalid YAML", "openapi: [", ErrInvalidDocument},
+		{"OpenAPI 3.0", `{"openapi": "3.0.3"}`, ErrUnsupportedVersion},
+		{"Swagger", `{"swagger": "2.0"}`, ErrUnsupportedVersion},
+		{
+			"unresolved schema reference",
+			`{"openapi": "3.1.0", "components": {"schemas": {"A": {"$ref": "#/components/schemas/B"}}}}`,
+			ErrReferenceResolution,
+		},
+		{
+			"unresolved parameter reference",
+			`{"openapi": "3.1.0", "paths": {"/a": {"get": {"parameters": [{"$ref": "#/components/parameters/P"}]}}}}`,
+			ErrReferenceResolution,
+		},
+		{
+			"external parameter reference",
+			`{"openapi": "3.1.0", "paths": {"/a": {"get": {"parameters": [{"$ref": "common.yaml#/P"}]}}}}`,
+			ErrReferenceResolution,
+		},
+		{
+			"parameter without a location",
+			`{"openapi": "3.1.0", "paths": {"/a": {"get": {"parameters": [{"name": "p"}]}}}}`,
+			ErrInvalidDocument,
+		},
+		{"unclosed path expression", `{"openapi": "3.1.0", "paths": {"/a/{id": {}}}`, ErrInvalidDocument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load([]byte(tt.source), LoadOptions{})
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	schema, err := jsonschema.NewCompiler().Compile([]byte(`{"type": ["integer", "boolean", "null"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("-12"), convert("-12", schema))
+	assert.Equal(t, true, convert("true", schema))
+	assert.Nil(t, convert("null", schema))
+	assert.Equal(t, " 1", convert(" 1", schema))
+	assert.Equal(t, "0x1", convert("0x1", schema))
+	assert.Equal(t, "1", convert("1", nil))
+}
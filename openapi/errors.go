@@ -0,0 +1,28 @@
+package openapi
+
+import "errors"
+
+var (
+	// ErrInvalidDocument reports a document that is not a well-formed
+	// OpenAPI description.
+	ErrInvalidDocument = errors.New("invalid OpenAPI document")
+
+	// ErrUnsupportedVersion reports a document of another OpenAPI version
+	// than 3.1.
+	ErrUnsupportedVersion = errors.New("unsupported OpenAPI version")
+
+	// ErrReferenceResolution reports a "$ref" Load cannot follow.
+	ErrReferenceResolution = errors.New("reference resolution failed")
+
+	// ErrOperationNotFound reports a request whose path matches no path
+	// template of the document.
+	ErrOperationNotFound = errors.New("operation not found")
+
+	// ErrMethodNotAllowed reports a request whose path matches a path
+	// template lacking an operation for the request method.
+	ErrMethodNotAllowed = errors.New("method not allowed")
+
+	// ErrValidationFailed reports a request or response that does not match
+	// its operation; the error is a *ValidationError listing the issues.
+	ErrValidationFailed = errors.New("validation failed")
+)
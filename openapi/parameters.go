@@ -0,0 +1,452 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// parameter is a Parameter or Header Object with its references followed.
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	style    string
+	explode  bool
+	schema   *jsonschema.Schema
+	media    string // Media type of "content", for parameters serialized as a document.
+}
+
+// defaultStyles are the "style" of parameters without one, by location.
+var defaultStyles = map[string]string{
+	"path":   "simple",
+	"query":  "form",
+	"header": "simple",
+	"cookie": "form",
+}
+
+// ignoredHeaders are the request headers OpenAPI describes with other
+// fields, so header parameters of these names are ignored.
+var ignoredHeaders = []string{"Accept", "Content-Type", "Authorization"}
+
+// key identifies p among the parameters of its location.
+func (p *parameter) key() string {
+	if p.in == "header" {
+		return http.CanonicalHeaderKey(p.name)
+	}
+	return p.name
+}
+
+func (d *Document) parameters(value any, location string) ([]*parameter, error) {
+	elements, _ := value.([]any)
+	params := make([]*parameter, 0, len(elements))
+	for i, element := range elements {
+		object, objectLocation, err := d.resolve(element, fmt.Sprintf("%s/%d", location, i))
+		if err != nil {
+			return nil, err
+		}
+		p, err := d.parameter(object, objectLocation)
+		if err != nil {
+			return nil, err
+		}
+		if p.in == "header" && slices.Contains(ignoredHeaders, p.key()) {
+			continue
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+func (d *Document) parameter(object map[string]any, location string) (*parameter, error) {
+	p := &parameter{}
+	p.name, _ = object["name"].(string)
+	p.in, _ = object["in"].(string)
+	if _, ok := defaultStyles[p.in]; !ok || p.name == "" {
+		return nil, fmt.Errorf("%w: parameter at #%s needs a name and a location", ErrInvalidDocument, location)
+	}
+	p.required, _ = object["required"].(bool)
+	p.required = p.required || p.in == "path"
+	p.style, _ = object["style"].(string)
+	if p.style == "" {
+		p.style = defaultStyles[p.in]
+	}
+	explode, ok := object["explode"].(bool)
+	p.explode = explode || (!ok && p.style == "form")
+
+	p.schema = d.schemas[location+"/schema"]
+	media, _ := object["content"].(map[string]any)
+	for key := range media {
+		mediaType, _, err := mime.ParseMediaType(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: media type %q at #%s", ErrInvalidDocument, key, location)
+		}
+		p.media = mediaType
+		p.schema = d.schemas[location+"/content/"+escapePointerToken(key)+"/schema"]
+	}
+	return p, nil
+}
+
+// requestValue returns the value of p in a request, decoded for its schema,
+// and whether the request has it.
+func (p *parameter) requestValue(header http.Header, pathValues map[string]string, query []queryPair) (any, bool, error) {
+	switch p.in {
+	case "path":
+		raw, ok := pathValues[p.name]
+		if !ok {
+			return nil, false, nil
+		}
+		value, err := p.decodeStyled(raw, url.PathUnescape)
+		return value, true, err
+	case "query":
+		return p.decodeQuery(query)
+	case "header":
+		return p.headerValue(header)
+	}
+	return nil, false, nil
+}
+
+// headerValue returns the value of the header parameter p in header.
+func (p *parameter) headerValue(header http.Header) (any, bool, error) {
+	values := header.Values(p.name)
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+	value, err := p.decodeStyled(strings.Join(values, ","), trimSpace)
+	return value, true, err
+}
+
+// decodeStyled decodes the "simple", "label" or "matrix" serialization raw of
+// p, unescaping its values with unescape.
+func (p *parameter) decodeStyled(raw string, unescape func(string) (string, error)) (any, error) {
+	if p.media != "" {
+		value, err := unescape(raw)
+		if err != nil {
+			return nil, err
+		}
+		return decodeContent(p.media, value)
+	}
+
+	malformed := fmt.Errorf("value %q does not match style %q", raw, p.style)
+	shape := shapeOf(p.schema)
+	pairs := p.explode && shape == "object" // Members serialized as "name=value".
+	var values []string
+	switch p.style {
+	case "simple":
+		values = splitValue(raw, ",", shape)
+	case "label":
+		rest, ok := strings.CutPrefix(raw, ".")
+		if !ok {
+			return nil, malformed
+		}
+		separator := ","
+		if p.explode {
+			separator = "."
+		}
+		values = splitValue(rest, separator, shape)
+	case "matrix":
+		rest, ok := strings.CutPrefix(raw, ";")
+		if !ok {
+			return nil, malformed
+		}
+		if pairs {
+			values = strings.Split(rest, ";")
+			break
+		}
+		for _, part := range strings.Split(rest, ";") {
+			name, value, _ := strings.Cut(part, "=")
+			if name != p.name {
+				return nil, malformed
+			}
+			values = append(values, value)
+		}
+		if !p.explode || shape != "array" {
+			if len(values) != 1 {
+				return nil, malformed
+			}
+			values = splitValue(values[0], ",", shape)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported style %q", p.style)
+	}
+	return p.assemble(values, shape, pairs, unescape)
+}
+
+// decodeQuery decodes the query parameter p from the pairs of a query.
+func (p *parameter) decodeQuery(query []queryPair) (any, bool, error) {
+	shape := shapeOf(p.schema)
+	switch {
+	case p.media != "":
+		raw, ok := lookupPair(query, p.name)
+		if !ok {
+			return nil, false, nil
+		}
+		value, err := url.QueryUnescape(raw)
+		if err != nil {
+			return nil, true, err
+		}
+		decoded, err := decodeContent(p.media, value)
+		return decoded, true, err
+	case p.style == "deepObject" || (p.explode && shape == "object"):
+		object := make(map[string]any)
+		for _, pair := range query {
+			name, ok := p.memberName(pair.name)
+			if !ok {
+				continue
+			}
+			value, err := url.QueryUnescape(pair.value)
+			if err != nil {
+				return nil, true, err
+			}
+			object[name] = convert(value, propertySchema(p.schema, name))
+		}
+		return object, len(object) > 0, nil
+	case p.explode && shape == "array":
+		var values []string
+		for _, pair := range query {
+			if pair.name == p.name {
+				values = append(values, pair.value)
+			}
+		}
+		if len(values) == 0 {
+			return nil, false, nil
+		}
+		value, err := p.assemble(values, shape, false, url.QueryUnescape)
+		return value, true, err
+	}
+
+	raw, ok := lookupPair(query, p.name)
+	if !ok {
+		return nil, false, nil
+	}
+	separator := ","
+	switch p.style {
+	case "spaceDelimited":
+		raw, separator = strings.ReplaceAll(raw, "+", "%20"), "%20"
+	case "pipeDelimited":
+		raw, separator = strings.ReplaceAll(strings.ReplaceAll(raw, "%7C", "|"), "%7c", "|"), "|"
+	}
+	value, err := p.assemble(splitValue(raw, separator, shape), shape, false, url.QueryUnescape)
+	return value, true, err
+}
+
+// memberName returns the property of the exploded object p a query pair
+// named pairName sets, if any.
+func (p *parameter) memberName(pairName string) (string, bool) {
+	if p.style == "deepObject" {
+		key, ok := strings.CutPrefix(pairName, p.name+"[")
+		if !ok {
+			return "", false
+		}
+		return strings.CutSuffix(key, "]")
+	}
+	return pairName, hasProperty(p.schema, pairName)
+}
+
+// assemble builds the value of p from its serialized values: the items of
+// an array, the members of an object as alternating names and values or as
+// "name=value" pairs, or a single primitive value.
+func (p *parameter) assemble(values []string, shape string, pairs bool, unescape func(string) (string, error)) (any, error) {
+	switch shape {
+	case "array":
+		items := make([]any, 0, len(values))
+		for _, raw := range values {
+			value, err := unescape(raw)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, convert(value, itemSchema(p.schema)))
+		}
+		return items, nil
+	case "object":
+		if !pairs && len(values)%2 != 0 {
+			return nil, fmt.Errorf("object %q has a name without a value", p.name)
+		}
+		object := make(map[string]any, len(values))
+		for i := 0; i < len(values); i++ {
+			rawName, rawValue := values[i], ""
+			if pairs {
+				var ok bool
+				if rawName, rawValue, ok = strings.Cut(rawName, "="); !ok {
+					return nil, fmt.Errorf("object %q has a member without a value", p.name)
+				}
+			} else {
+				i++
+				rawValue = values[i]
+			}
+			name, err := unescape(rawName)
+			if err != nil {
+				return nil, err
+			}
+			value, err := unescape(rawValue)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = convert(value, propertySchema(p.schema, name))
+		}
+		return object, nil
+	}
+	value, err := unescape(strings.Join(values, ","))
+	if err != nil {
+		return nil, err
+	}
+	return convert(value, p.schema), nil
+}
+
+// queryPair is a member of a query string, with its name unescaped and its
+// value as sent.
+type queryPair struct {
+	name  string
+	value string
+}
+
+// parseQuery splits a query string, keeping the values escaped so that
+// delimiters within them can be told from delimiters between them.
+func parseQuery(rawQuery string) []queryPair {
+	var pairs []queryPair
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		rawName, value, _ := strings.Cut(part, "=")
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			name = rawName
+		}
+		pairs = append(pairs, queryPair{name: name, value: value})
+	}
+	return pairs
+}
+
+func lookupPair(query []queryPair, name string) (string, bool) {
+	for _, pair := range query {
+		if pair.name == name {
+			return pair.value, true
+		}
+	}
+	return "", false
+}
+
+// splitValue splits the serialized array or object raw on separator. The
+// values of primitive parameters are not split.
+func splitValue(raw, separator, shape string) []string {
+	switch {
+	case shape == "":
+		return []string{raw}
+	case raw == "":
+		return nil
+	}
+	return strings.Split(raw, separator)
+}
+
+func trimSpace(value string) (string, error) {
+	return strings.TrimSpace(value), nil
+}
+
+// convert turns value into the JSON type its schema expects, so that "7"
+// validates against {"type": "integer"}. Values no type accepts stay
+// strings.
+func convert(value string, schema *jsonschema.Schema) any {
+	types := typesOf(schema)
+	switch {
+	case (slices.Contains(types, "integer") || slices.Contains(types, "number")) && isJSONNumber(value):
+		return json.Number(value)
+	case slices.Contains(types, "boolean") && (value == "true" || value == "false"):
+		return value == "true"
+	case slices.Contains(types, "null") && value == "null":
+		return nil
+	}
+	return value
+}
+
+func isJSONNumber(value string) bool {
+	return value != "" && strings.TrimSpace(value) == value &&
+		(value[0] == '-' || (value[0] >= '0' && value[0] <= '9')) && json.Valid([]byte(value))
+}
+
+// decodeContent decodes a value serialized as mediaType.
+func decodeContent(mediaType, value string) (any, error) {
+	if !isJSONMediaType(mediaType) {
+		return value, nil
+	}
+	decoded, err := decodeJSON([]byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("value is not valid JSON: %w", err)
+	}
+	return decoded, nil
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// resolved follows the "$ref" of schema while it declares no type of its own.
+func resolved(schema *jsonschema.Schema) *jsonschema.Schema {
+	for range 32 {
+		if schema == nil || len(schema.Type) > 0 || schema.ResolvedRef == nil {
+			break
+		}
+		schema = schema.ResolvedRef
+	}
+	return schema
+}
+
+func typesOf(schema *jsonschema.Schema) []string {
+	if schema = resolved(schema); schema == nil {
+		return nil
+	}
+	return schema.Type
+}
+
+// shapeOf tells whether schema describes an "array", an "object", or a
+// primitive value, for which it returns "".
+func shapeOf(schema *jsonschema.Schema) string {
+	if schema = resolved(schema); schema == nil {
+		return ""
+	}
+	switch {
+	case slices.Contains(schema.Type, "array"):
+		return "array"
+	case slices.Contains(schema.Type, "object"):
+		return "object"
+	case len(schema.Type) > 0:
+		return ""
+	case schema.Items != nil || len(schema.PrefixItems) > 0:
+		return "array"
+	case schema.Properties != nil || schema.AdditionalProperties != nil:
+		return "object"
+	}
+	return ""
+}
+
+func itemSchema(schema *jsonschema.Schema) *jsonschema.Schema {
+	if schema = resolved(schema); schema == nil {
+		return nil
+	}
+	return schema.Items
+}
+
+func hasProperty(schema *jsonschema.Schema, name string) bool {
+	if schema = resolved(schema); schema == nil || schema.Properties == nil {
+		return false
+	}
+	_, ok := (*schema.Properties)[name]
+	return ok
+}
+
+func propertySchema(schema *jsonschema.Schema, name string) *jsonschema.Schema {
+	if schema = resolved(schema); schema == nil {
+		return nil
+	}
+	if schema.Properties != nil {
+		if property, ok := (*schema.Properties)[name]; ok {
+			return property
+		}
+	}
+	return schema.AdditionalProperties
+}
@@ -0,0 +1,282 @@
+package openapi
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// route is a path template of the document with its operations.
+type route struct {
+	template   string
+	pattern    *regexp.Regexp
+	names      []string // Path parameter names, in template order.
+	literals   int      // Length of the template outside its expressions.
+	operations map[string]*operation
+}
+
+// operation is an Operation Object with its references followed.
+type operation struct {
+	method    string
+	template  string
+	params    []*parameter
+	body      *content
+	responses map[string]*response // By status code, "NXX" range or "default".
+}
+
+// content is the "content" of a Request Body or Response Object.
+type content struct {
+	required bool
+	media    map[string]*jsonschema.Schema // By media type; a nil schema allows any body.
+}
+
+// response is a Response Object with its references followed.
+type response struct {
+	headers []*parameter
+	body    *content
+}
+
+// buildRoutes reads the paths and servers of the document.
+func (d *Document) buildRoutes() error {
+	d.prefixes = serverPrefixes(d.document["servers"])
+
+	paths, _ := d.document["paths"].(map[string]any)
+	for _, template := range slices.Sorted(maps.Keys(paths)) {
+		item, location, err := d.resolve(paths[template], "/paths/"+escapePointerToken(template))
+		if err != nil {
+			return err
+		}
+		r, err := newRoute(template)
+		if err != nil {
+			return err
+		}
+		shared, err := d.parameters(item["parameters"], location+"/parameters")
+		if err != nil {
+			return err
+		}
+		for _, method := range httpMethods {
+			value, ok := item[method]
+			if !ok {
+				continue
+			}
+			op, err := d.operation(value, location+"/"+method, shared)
+			if err != nil {
+				return err
+			}
+			op.method, op.template = strings.ToUpper(method), template
+			r.operations[method] = op
+		}
+		d.routes = append(d.routes, r)
+	}
+
+	// Concrete paths win over templated ones, as OpenAPI requires.
+	slices.SortStableFunc(d.routes, func(a, b *route) int {
+		return cmp.Or(cmp.Compare(len(a.names), len(b.names)), cmp.Compare(b.literals, a.literals))
+	})
+	return nil
+}
+
+func (d *Document) operation(value any, location string, shared []*parameter) (*operation, error) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: #%s is not an object", ErrInvalidDocument, location)
+	}
+	params, err := d.parameters(object["parameters"], location+"/parameters")
+	if err != nil {
+		return nil, err
+	}
+	op := &operation{params: params, responses: make(map[string]*response)}
+	for _, p := range shared {
+		overridden := slices.ContainsFunc(params, func(q *parameter) bool {
+			return q.in == p.in && q.key() == p.key()
+		})
+		if !overridden {
+			op.params = append(op.params, p)
+		}
+	}
+
+	if body, ok := object["requestBody"]; ok {
+		if op.body, err = d.content(body, location+"/requestBody"); err != nil {
+			return nil, err
+		}
+	}
+	responses, _ := object["responses"].(map[string]any)
+	for status, value := range responses {
+		if op.responses[strings.ToUpper(status)], err = d.response(value, location+"/responses/"+escapePointerToken(status)); err != nil {
+			return nil, err
+		}
+	}
+	return op, nil
+}
+
+func (d *Document) response(value any, location string) (*response, error) {
+	object, location, err := d.resolve(value, location)
+	if err != nil {
+		return nil, err
+	}
+	resp := &response{}
+	headers, _ := object["headers"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		header, headerLocation, err := d.resolve(headers[name], location+"/headers/"+escapePointerToken(name))
+		if err != nil {
+			return nil, err
+		}
+		header = maps.Clone(header)
+		header["name"], header["in"] = name, "header"
+		p, err := d.parameter(header, headerLocation)
+		if err != nil {
+			return nil, err
+		}
+		resp.headers = append(resp.headers, p)
+	}
+	if _, ok := object["content"]; ok {
+		if resp.body, err = d.content(object, location); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// content reads the media types of the "content" of value.
+func (d *Document) content(value any, location string) (*content, error) {
+	object, location, err := d.resolve(value, location)
+	if err != nil {
+		return nil, err
+	}
+	required, _ := object["required"].(bool)
+	c := &content{required: required, media: make(map[string]*jsonschema.Schema)}
+	media, _ := object["content"].(map[string]any)
+	for key := range media {
+		mediaType, _, err := mime.ParseMediaType(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: media type %q at #%s", ErrInvalidDocument, key, location)
+		}
+		c.media[mediaType] = d.schemas[location+"/content/"+escapePointerToken(key)+"/schema"]
+	}
+	return c, nil
+}
+
+// lookup returns the schema of the media type matching contentType, trying
+// the exact type, then "type/*", then "*/*".
+func (c *content) lookup(contentType string) (mediaType string, schema *jsonschema.Schema, ok bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", nil, false
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, candidate := range []string{mediaType, major + "/*", "*/*"} {
+		if schema, ok := c.media[candidate]; ok {
+			return mediaType, schema, true
+		}
+	}
+	return mediaType, nil, false
+}
+
+func newRoute(template string) (*route, error) {
+	r := &route{template: template, operations: make(map[string]*operation)}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	rest := template
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unclosed expression in path %q", ErrInvalidDocument, template)
+		}
+		pattern.WriteString(regexp.QuoteMeta(rest[:start]))
+		pattern.WriteString("([^/]+)")
+		r.literals += start
+		r.names = append(r.names, rest[start+1:start+end])
+		rest = rest[start+end+1:]
+	}
+	pattern.WriteString(regexp.QuoteMeta(rest))
+	pattern.WriteString("$")
+	r.literals += len(rest)
+
+	var err error
+	if r.pattern, err = regexp.Compile(pattern.String()); err != nil {
+		return nil, fmt.Errorf("%w: path %q: %w", ErrInvalidDocument, template, err)
+	}
+	return r, nil
+}
+
+// serverPrefixes returns the paths of the server URLs, with their variables
+// set to their defaults, longest first. Without servers requests are matched
+// from the root.
+func serverPrefixes(value any) []string {
+	servers, _ := value.([]any)
+	var prefixes []string
+	for _, value := range servers {
+		server, _ := value.(map[string]any)
+		serverURL, _ := server["url"].(string)
+		variables, _ := server["variables"].(map[string]any)
+		for name, variable := range variables {
+			object, _ := variable.(map[string]any)
+			fallback, _ := object["default"].(string)
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", fallback)
+		}
+		parsed, err := url.Parse(serverURL)
+		if err != nil {
+			continue
+		}
+		prefix := strings.TrimSuffix(parsed.EscapedPath(), "/")
+		if !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		return []string{""}
+	}
+	slices.SortFunc(prefixes, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	return prefixes
+}
+
+// findOperation returns the operation of r and its raw path parameters.
+func (d *Document) findOperation(r *http.Request) (*operation, map[string]string, error) {
+	path := r.URL.EscapedPath()
+	method := strings.ToLower(r.Method)
+	matched := false
+	for _, prefix := range d.prefixes {
+		rest, ok := strings.CutPrefix(path, prefix)
+		if !ok || (rest != "" && rest[0] != '/') {
+			continue
+		}
+		if rest == "" {
+			rest = "/"
+		}
+		for _, route := range d.routes {
+			values := route.pattern.FindStringSubmatch(rest)
+			if values == nil {
+				continue
+			}
+			matched = true
+			op, ok := route.operations[method]
+			if !ok {
+				continue
+			}
+			params := make(map[string]string, len(route.names))
+			for i, name := range route.names {
+				params[name] = values[i+1]
+			}
+			return op, params, nil
+		}
+	}
+	if matched {
+		return nil, nil, fmt.Errorf("%w: %s %s", ErrMethodNotAllowed, r.Method, path)
+	}
+	return nil, nil, fmt.Errorf("%w: %s %s", ErrOperationNotFound, r.Method, path)
+}
@@ -0,0 +1,276 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// Issue is a part of a request or response that does not match its
+// operation.
+type Issue struct {
+	// In is where the issue is: "path", "query" or "header" for parameters
+	// and headers, "body", or "status" for an undocumented response status.
+	In string
+	// Name is the parameter or header name, the media type of the body, or
+	// the status code.
+	Name string
+	// Message describes the issue.
+	Message string
+	// Result is the evaluation result of the schema that rejected the value,
+	// or nil when no schema was evaluated.
+	Result *jsonschema.EvaluationResult
+}
+
+// String returns the issue as "in name: message".
+func (i Issue) String() string {
+	switch {
+	case i.In == "status":
+		return i.Message
+	case i.Name == "":
+		return i.In + ": " + i.Message
+	}
+	return fmt.Sprintf("%s %q: %s", i.In, i.Name, i.Message)
+}
+
+// ValidationError lists the issues of a request or response. It matches
+// ErrValidationFailed with errors.Is.
+type ValidationError struct {
+	// Method and Path identify the operation, such as "GET" and
+	// "/pets/{id}".
+	Method string
+	Path   string
+	Issues []Issue
+}
+
+// Error returns the issues joined into one message.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.String()
+	}
+	return fmt.Sprintf("%s for %s %s: %s", ErrValidationFailed, e.Method, e.Path, strings.Join(messages, "; "))
+}
+
+// Is reports whether target is ErrValidationFailed.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// ValidateRequest checks r against its operation, found by path template and
+// method. Path, query and header parameters are decoded per their "style"
+// and "explode" and validated against their schemas, and the body against
+// the schema of its media type. Cookie parameters are not checked.
+//
+// ValidateRequest fails with ErrOperationNotFound or ErrMethodNotAllowed
+// when the document has no operation for r, and with a *ValidationError
+// when r does not match it. The body of r is read and replaced, so handlers
+// can still read it.
+func (d *Document) ValidateRequest(r *http.Request) error {
+	op, pathValues, err := d.findOperation(r)
+	if err != nil {
+		return err
+	}
+	var issues []Issue
+	query := parseQuery(r.URL.RawQuery)
+	for _, p := range op.params {
+		value, present, err := p.requestValue(r.Header, pathValues, query)
+		issues = p.check(issues, value, present, err)
+	}
+	if op.body != nil {
+		data, err := readBody(&r.Body)
+		if err != nil {
+			return fmt.Errorf("reading request body: %w", err)
+		}
+		issues = op.body.check(issues, r.Header.Get("Content-Type"), data)
+	}
+	return op.validationError(issues)
+}
+
+// ValidateResponse checks resp against the operation of r: its status code
+// must be documented, by code, range such as "2XX", or "default", and its
+// headers and body must match the response. It fails like ValidateRequest,
+// and reads and replaces the body of resp.
+func (d *Document) ValidateResponse(r *http.Request, resp *http.Response) error {
+	op, _, err := d.findOperation(r)
+	if err != nil {
+		return err
+	}
+	status := strconv.Itoa(resp.StatusCode)
+	documented := op.response(resp.StatusCode)
+	if documented == nil {
+		return op.validationError([]Issue{{In: "status", Name: status, Message: "status " + status + " is not documented"}})
+	}
+
+	var issues []Issue
+	for _, header := range documented.headers {
+		value, present, err := header.headerValue(resp.Header)
+		issues = header.check(issues, value, present, err)
+	}
+	if documented.body != nil {
+		data, err := readBody(&resp.Body)
+		if err != nil {
+			return fmt.Errorf("reading response body: %w", err)
+		}
+		issues = documented.body.check(issues, resp.Header.Get("Content-Type"), data)
+	}
+	return op.validationError(issues)
+}
+
+// response returns the response documented for status, or nil.
+func (op *operation) response(status int) *response {
+	for _, key := range []string{strconv.Itoa(status), strconv.Itoa(status/100) + "XX", "DEFAULT"} {
+		if documented, ok := op.responses[key]; ok {
+			return documented
+		}
+	}
+	return nil
+}
+
+func (op *operation) validationError(issues []Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	return &ValidationError{Method: op.method, Path: op.template, Issues: issues}
+}
+
+// check appends the issue of the value of p, if any, to issues.
+func (p *parameter) check(issues []Issue, value any, present bool, err error) []Issue {
+	switch {
+	case !present:
+		if p.required {
+			issues = append(issues, Issue{In: p.in, Name: p.name, Message: "is required"})
+		}
+	case err != nil:
+		issues = append(issues, Issue{In: p.in, Name: p.name, Message: err.Error()})
+	case p.schema != nil:
+		if result := p.schema.Validate(value); !result.IsValid() {
+			issues = append(issues, Issue{In: p.in, Name: p.name, Message: resultMessage(result), Result: result})
+		}
+	}
+	return issues
+}
+
+// check appends the issue of the body data of type contentType, if any, to
+// issues. Bodies of media types that are not JSON, form data or text are
+// only matched by media type.
+func (c *content) check(issues []Issue, contentType string, data []byte) []Issue {
+	if len(data) == 0 {
+		if c.required {
+			issues = append(issues, Issue{In: "body", Message: "is required"})
+		}
+		return issues
+	}
+	if len(c.media) == 0 {
+		return issues
+	}
+	mediaType, schema, ok := c.lookup(contentType)
+	if !ok {
+		return append(issues, Issue{In: "body", Name: mediaType, Message: fmt.Sprintf("media type %q is not allowed", contentType)})
+	}
+	if schema == nil {
+		return issues
+	}
+	value, decoded, err := decodeBody(contentType, data, schema)
+	switch {
+	case err != nil:
+		issues = append(issues, Issue{In: "body", Name: mediaType, Message: err.Error()})
+	case decoded:
+		if result := schema.Validate(value); !result.IsValid() {
+			issues = append(issues, Issue{In: "body", Name: mediaType, Message: resultMessage(result), Result: result})
+		}
+	}
+	return issues
+}
+
+// decodeBody decodes a body of JSON, form data or text into the value its
+// schema validates, and reports whether it knows the media type.
+func decodeBody(contentType string, data []byte, schema *jsonschema.Schema) (any, bool, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false, err
+	}
+	switch {
+	case isJSONMediaType(mediaType):
+		value, err := decodeJSON(data)
+		if err != nil {
+			return nil, true, fmt.Errorf("body is not valid JSON: %w", err)
+		}
+		return value, true, nil
+	case mediaType == "application/x-www-form-urlencoded":
+		object := make(map[string]any)
+		for _, pair := range parseQuery(string(data)) {
+			value, err := url.QueryUnescape(pair.value)
+			if err != nil {
+				return nil, true, err
+			}
+			addMember(object, pair.name, value, schema)
+		}
+		return object, true, nil
+	case mediaType == "multipart/form-data":
+		object := make(map[string]any)
+		reader := multipart.NewReader(bytes.NewReader(data), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return object, true, nil
+			}
+			if err != nil {
+				return nil, true, fmt.Errorf("body is not valid multipart data: %w", err)
+			}
+			value, err := io.ReadAll(part)
+			if err != nil {
+				return nil, true, fmt.Errorf("body is not valid multipart data: %w", err)
+			}
+			addMember(object, part.FormName(), string(value), schema)
+		}
+	case strings.HasPrefix(mediaType, "text/"):
+		return string(data), true, nil
+	}
+	return nil, false, nil
+}
+
+// addMember sets the form field name of object to value, collecting the
+// values of repeated fields whose property is an array.
+func addMember(object map[string]any, name, value string, schema *jsonschema.Schema) {
+	property := propertySchema(schema, name)
+	if shapeOf(property) != "array" {
+		object[name] = convert(value, property)
+		return
+	}
+	items, _ := object[name].([]any)
+	object[name] = append(items, convert(value, itemSchema(property)))
+}
+
+// resultMessage joins the leaf errors of result, prefixed with their
+// instance location.
+func resultMessage(result *jsonschema.EvaluationResult) string {
+	var messages []string
+	for leaf := range result.AllErrors() {
+		if leaf.InstanceLocation == "" {
+			messages = append(messages, leaf.Error())
+		} else {
+			messages = append(messages, leaf.InstanceLocation+": "+leaf.Error())
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// readBody reads *body and replaces it with a reader of the same data.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
@@ -0,0 +1,219 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const requestID = "7b0c3d8e-2a4f-4c55-9a6e-1f2d3c4b5a69"
+
+func newRequest(method, target, contentType, body string) *http.Request {
+	r := httptest.NewRequest(method, "https://api.example.com/v1"+target, strings.NewReader(body))
+	r.Header.Set("X-Request-ID", requestID)
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+// issues returns the issues of err, a *ValidationError, as strings.
+func issues(t *testing.T, err error) []string {
+	t.Helper()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.ErrorIs(t, err, ErrValidationFailed)
+	var messages []string
+	for _, issue := range validationErr.Issues {
+		messages = append(messages, issue.In+" "+issue.Name)
+	}
+	return messages
+}
+
+func TestValidateRequestParameters(t *testing.T) {
+	document := loadPetstore(t)
+	tests := []struct {
+		name   string
+		target string
+		issues []string
+	}{
+		{"no parameters", "/pets", nil},
+		{"form", "/pets?limit=10&tags=a&tags=b", nil},
+		{"form maximum", "/pets?limit=101", []string{"query limit"}},
+		{"form type", "/pets?limit=ten", []string{"query limit"}},
+		{"form not exploded", "/pets?ids=1,2,3", nil},
+		{"form not exploded item type", "/pets?ids=1,two", []string{"query ids"}},
+		{"pipe delimited", "/pets?sizes=s|m%7Cl", nil},
+		{"pipe delimited enum", "/pets?sizes=s|xl", []string{"query sizes"}},
+		{"deep object", "/pets?filter[age]=3", nil},
+		{"deep object member", "/pets?filter[age]=3&filter[color]=red", []string{"query filter"}},
+		{"path", "/pets/12", nil},
+		{"path minimum", "/pets/0", []string{"path id"}},
+		{"concrete path", "/pets/mine", nil},
+		{"matrix exploded object", "/points/;x=1;y=2", nil},
+		{"matrix missing member", "/points/;x=1", []string{"path point"}},
+		{"matrix malformed", "/points/x=1", []string{"path point"}},
+		{"content parameter", "/points/;x=1;y=2?where=%7B%22x%22%3A1%2C%22y%22%3A2%7D", nil},
+		{"content parameter schema", "/points/;x=1;y=2?where=%7B%22x%22%3A1%7D", []string{"query where"}},
+		{"content parameter JSON", "/points/;x=1;y=2?where=%7B", []string{"query where"}},
+		{"label", "/labels/.a,b", nil},
+		{"label min items", "/labels/.a", []string{"path labels"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := document.ValidateRequest(newRequest(http.MethodGet, tt.target, "", ""))
+			if tt.issues == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.issues, issues(t, err))
+		})
+	}
+}
+
+func TestValidateRequestHeaders(t *testing.T) {
+	document := loadPetstore(t)
+
+	r := newRequest(http.MethodGet, "/pets", "", "")
+	r.Header.Del("X-Request-ID")
+	err := document.ValidateRequest(r)
+	assert.Equal(t, []string{"header X-Request-ID"}, issues(t, err))
+	assert.Contains(t, err.Error(), `header "X-Request-ID": is required`)
+
+	r = newRequest(http.MethodGet, "/pets", "", "")
+	r.Header.Set("X-Request-ID", "not a uuid")
+	assert.Equal(t, []string{"header X-Request-ID"}, issues(t, document.ValidateRequest(r)))
+
+	r = newRequest(http.MethodGet, "/points/;x=1;y=2", "", "")
+	r.Header.Add("X-Coords", "1.5, 2")
+	r.Header.Add("X-Coords", "3")
+	assert.NoError(t, document.ValidateRequest(r))
+	r.Header.Add("X-Coords", "north")
+	assert.Equal(t, []string{"header X-Coords"}, issues(t, document.ValidateRequest(r)))
+}
+
+func TestValidateRequestOperation(t *testing.T) {
+	document := loadPetstore(t)
+
+	assert.NoError(t, document.ValidateRequest(newRequest(http.MethodDelete, "/pets/abc", "", "")), "operation parameters override path item parameters")
+	assert.ErrorIs(t, document.ValidateRequest(newRequest(http.MethodPut, "/pets/1", "", "")), ErrMethodNotAllowed)
+	assert.ErrorIs(t, document.ValidateRequest(newRequest(http.MethodGet, "/owners", "", "")), ErrOperationNotFound)
+
+	r := httptest.NewRequest(http.MethodGet, "https://api.example.com/pets", nil)
+	assert.ErrorIs(t, document.ValidateRequest(r), ErrOperationNotFound, "paths are relative to the server URL")
+}
+
+func TestValidateRequestBody(t *testing.T) {
+	document := loadPetstore(t)
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		issues      []string
+	}{
+		{"JSON", "application/json", `{"name": "Rex", "age": 2}`, nil},
+		{"JSON with parameters", "application/json; charset=utf-8", `{"name": "Rex"}`, nil},
+		{"JSON schema", "application/json", `{"age": -1}`, []string{"body application/json"}},
+		{"invalid JSON", "application/json", `{"name":`, []string{"body application/json"}},
+		{"missing", "application/json", ``, []string{"body "}},
+		{"form", "application/x-www-form-urlencoded", "name=Rex+Jr&age=2&tags=a&tags=b", nil},
+		{"form schema", "application/x-www-form-urlencoded", "age=two", []string{"body application/x-www-form-urlencoded"}},
+		{"text wildcard", "text/plain", "hello", nil},
+		{"text schema", "text/csv", "too long", []string{"body text/csv"}},
+		{"media type", "application/xml", "<pet/>", []string{"body application/xml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRequest(http.MethodPost, "/pets", tt.contentType, tt.body)
+			err := document.ValidateRequest(r)
+			if tt.issues == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.issues, issues(t, err))
+			}
+			body, readErr := io.ReadAll(r.Body)
+			require.NoError(t, readErr)
+			assert.Equal(t, tt.body, string(body), "the body can be read again")
+		})
+	}
+}
+
+func TestValidateRequestMultipart(t *testing.T) {
+	document, err := Load([]byte(`
+openapi: 3.1.0
+info: {title: Upload, version: "1"}
+paths:
+  /upload:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [file]
+              properties:
+                count: {type: integer}
+                file: {type: string}
+      responses:
+        "204": {description: Uploaded.}
+`), LoadOptions{})
+	require.NoError(t, err)
+
+	upload := func(fields map[string]string) *http.Request {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for name, value := range fields {
+			require.NoError(t, writer.WriteField(name, value))
+		}
+		require.NoError(t, writer.Close())
+		r := httptest.NewRequest(http.MethodPost, "/upload", &body)
+		r.Header.Set("Content-Type", writer.FormDataContentType())
+		return r
+	}
+	assert.NoError(t, document.ValidateRequest(upload(map[string]string{"count": "2", "file": "PNG"})))
+	assert.Equal(t, []string{"body multipart/form-data"}, issues(t, document.ValidateRequest(upload(map[string]string{"count": "two"}))))
+}
+
+func TestValidateResponse(t *testing.T) {
+	document := loadPetstore(t)
+	newResponse := func(status int, contentType, body string, header map[string]string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+		if contentType != "" {
+			resp.Header.Set("Content-Type", contentType)
+		}
+		for name, value := range header {
+			resp.Header.Set(name, value)
+		}
+		return resp
+	}
+	list := newRequest(http.MethodGet, "/pets", "", "")
+	total := map[string]string{"X-Total": "1"}
+
+	assert.NoError(t, document.ValidateResponse(list, newResponse(200, "application/json", `[{"name": "Rex"}]`, total)))
+	assert.Equal(t, []string{"body application/json"},
+		issues(t, document.ValidateResponse(list, newResponse(200, "application/json", `[{"age": 1}]`, total))))
+	assert.Equal(t, []string{"header X-Total"},
+		issues(t, document.ValidateResponse(list, newResponse(200, "application/json", `[]`, nil))))
+	assert.Equal(t, []string{"header X-Total"},
+		issues(t, document.ValidateResponse(list, newResponse(200, "application/json", `[]`, map[string]string{"X-Total": "many"}))))
+
+	assert.NoError(t, document.ValidateResponse(list, newResponse(500, "application/problem+json", `{"title": "Oops"}`, nil)), "default response")
+	assert.Equal(t, []string{"body application/problem+json"},
+		issues(t, document.ValidateResponse(list, newResponse(500, "application/problem+json", `{}`, nil))))
+
+	get := newRequest(http.MethodGet, "/pets/1", "", "")
+	assert.NoError(t, document.ValidateResponse(get, newResponse(204, "", "", nil)), "status code range")
+	err := document.ValidateResponse(get, newResponse(404, "", "", nil))
+	assert.Equal(t, []string{"status 404"}, issues(t, err))
+	assert.Contains(t, err.Error(), "GET /pets/{id}")
+
+	assert.True(t, errors.Is(document.ValidateResponse(newRequest(http.MethodGet, "/none", "", ""), newResponse(200, "", "", nil)), ErrOperationNotFound))
+}
@@ -0,0 +1,110 @@
+package jsonschema
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/go-json-experiment/json"
+)
+
+// applyOpenAPI30Keywords applies OpenAPI 3.0 "nullable", which adds "null" to
+// the types of "type" in the same schema. Without "type" it has no effect, and
+// "enum" still has to list null to accept it.
+func (s *Schema) applyOpenAPI30Keywords() error {
+	if s.dialect != OpenAPI30 {
+		return nil
+	}
+	raw, ok := s.rawExtra["nullable"]
+	if !ok {
+		return nil
+	}
+	var nullable bool
+	if err := json.Unmarshal(raw, &nullable); err != nil {
+		return fmt.Errorf("nullable: %w", err)
+	}
+	if nullable && len(s.Type) > 0 && !slices.Contains(s.Type, "null") {
+		s.Type = append(s.Type, "null")
+	}
+	delete(s.rawExtra, "nullable")
+	return nil
+}
+
+// openAPI30NumericFormats are the OpenAPI 3.0 formats refining "integer" and
+// "number". They are part of the data type, so the OpenAPI30 dialect asserts
+// them even when format assertion is off.
+var openAPI30NumericFormats = map[string]func(any) bool{
+	"int32":  IsInt32,
+	"int64":  IsInt64,
+	"float":  IsFloat,
+	"double": IsDouble,
+}
+
+// openAPI30Formats are the other OpenAPI 3.0 formats, asserted like any
+// format when Compiler.SetAssertFormat is on.
+var openAPI30Formats = map[string]func(any) bool{
+	"byte":     IsByte,
+	"binary":   func(any) bool { return true },
+	"password": func(any) bool { return true },
+}
+
+var (
+	minInt32   = big.NewRat(math.MinInt32, 1)
+	maxInt32   = big.NewRat(math.MaxInt32, 1)
+	minInt64   = big.NewRat(math.MinInt64, 1)
+	maxInt64   = big.NewRat(math.MaxInt64, 1)
+	maxFloat32 = new(big.Rat).SetFloat64(math.MaxFloat32)
+	maxFloat64 = new(big.Rat).SetFloat64(math.MaxFloat64)
+)
+
+// IsInt32 tells whether given number is an integer a signed 32-bit integer
+// can hold, as the OpenAPI 3.0 "int32" format requires.
+func IsInt32(v any) bool {
+	return inNumericRange(v, true, minInt32, maxInt32)
+}
+
+// IsInt64 tells whether given number is an integer a signed 64-bit integer
+// can hold, as the OpenAPI 3.0 "int64" format requires.
+func IsInt64(v any) bool {
+	return inNumericRange(v, true, minInt64, maxInt64)
+}
+
+// IsFloat tells whether given number is within the range of an IEEE 754
+// single-precision float, as the OpenAPI 3.0 "float" format requires.
+func IsFloat(v any) bool {
+	return inNumericRange(v, false, new(big.Rat).Neg(maxFloat32), maxFloat32)
+}
+
+// IsDouble tells whether given number is within the range of an IEEE 754
+// double-precision float, as the OpenAPI 3.0 "double" format requires.
+func IsDouble(v any) bool {
+	return inNumericRange(v, false, new(big.Rat).Neg(maxFloat64), maxFloat64)
+}
+
+// inNumericRange compares v exactly against [lower, upper]. Values that are
+// not numbers pass, as for the other formats.
+func inNumericRange(v any, integer bool, lower, upper *big.Rat) bool {
+	number, ok := numberRat(v)
+	if !ok || number == nil {
+		return true
+	}
+	if integer && !number.IsInt() {
+		return false
+	}
+	return number.Cmp(lower) >= 0 && number.Cmp(upper) <= 0
+}
+
+// IsByte tells whether given string is base64 encoded data, as the OpenAPI
+// 3.0 "byte" format requires.
+//
+// see https://datatracker.ietf.org/doc/html/rfc4648#section-4, for details
+func IsByte(v any) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := base64.StdEncoding.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,156 @@
+package jsonschema
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// orderedObject is a JSON object that keeps the order of its members, for
+// rewriting documents their authors will read again. Values are
+// *orderedObject, []any or a scalar jsontext.Value.
+type orderedObject struct {
+	members []orderedMember
+	renamed map[string]string // Members renamed by a rewrite, by old name.
+}
+
+type orderedMember struct {
+	name  string
+	value any
+}
+
+func (o *orderedObject) index(name string) int {
+	return slices.IndexFunc(o.members, func(m orderedMember) bool { return m.name == name })
+}
+
+func (o *orderedObject) get(name string) (any, bool) {
+	if i := o.index(name); i >= 0 {
+		return o.members[i].value, true
+	}
+	return nil, false
+}
+
+// set replaces the value of name in place, or appends it.
+func (o *orderedObject) set(name string, value any) {
+	if i := o.index(name); i >= 0 {
+		o.members[i].value = value
+		return
+	}
+	o.members = append(o.members, orderedMember{name: name, value: value})
+}
+
+func (o *orderedObject) insert(index int, name string, value any) {
+	o.members = slices.Insert(o.members, index, orderedMember{name: name, value: value})
+}
+
+func (o *orderedObject) remove(name string) {
+	if i := o.index(name); i >= 0 {
+		o.members = slices.Delete(o.members, i, i+1)
+	}
+}
+
+// replace swaps the member old for name with value, in the same place.
+func (o *orderedObject) replace(old, name string, value any) {
+	if i := o.index(old); i >= 0 {
+		o.members[i] = orderedMember{name: name, value: value}
+		o.rename(old, name)
+	}
+}
+
+// rename records that the member old is now called name, so JSON Pointers
+// into the object can follow.
+func (o *orderedObject) rename(old, name string) {
+	if o.renamed == nil {
+		o.renamed = make(map[string]string)
+	}
+	o.renamed[old] = name
+}
+
+// decodeOrderedJSON decodes data keeping the order of object members.
+func decodeOrderedJSON(data []byte) (any, error) {
+	decoder := jsontext.NewDecoder(bytes.NewReader(data))
+	value, err := decodeOrderedValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.ReadToken(); err == nil {
+		return nil, ErrJSONUnmarshal
+	}
+	return value, nil
+}
+
+func decodeOrderedValue(decoder *jsontext.Decoder) (any, error) {
+	switch decoder.PeekKind() {
+	case '{':
+		if _, err := decoder.ReadToken(); err != nil {
+			return nil, err
+		}
+		object := &orderedObject{}
+		for decoder.PeekKind() != '}' {
+			token, err := decoder.ReadToken()
+			if err != nil {
+				return nil, err
+			}
+			name := token.String()
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.members = append(object.members, orderedMember{name: name, value: value})
+		}
+		_, err := decoder.ReadToken()
+		return object, err
+	case '[':
+		if _, err := decoder.ReadToken(); err != nil {
+			return nil, err
+		}
+		elements := []any{}
+		for decoder.PeekKind() != ']' {
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, value)
+		}
+		_, err := decoder.ReadToken()
+		return elements, err
+	default:
+		value, err := decoder.ReadValue()
+		if err != nil {
+			return nil, err
+		}
+		return value.Clone(), nil
+	}
+}
+
+// encodeOrderedJSON encodes a value decoded by decodeOrderedJSON.
+func encodeOrderedJSON(encoder *jsontext.Encoder, value any) error {
+	switch value := value.(type) {
+	case *orderedObject:
+		if err := encoder.WriteToken(jsontext.BeginObject); err != nil {
+			return err
+		}
+		for _, member := range value.members {
+			if err := encoder.WriteToken(jsontext.String(member.name)); err != nil {
+				return err
+			}
+			if err := encodeOrderedJSON(encoder, member.value); err != nil {
+				return err
+			}
+		}
+		return encoder.WriteToken(jsontext.EndObject)
+	case []any:
+		if err := encoder.WriteToken(jsontext.BeginArray); err != nil {
+			return err
+		}
+		for _, element := range value {
+			if err := encodeOrderedJSON(encoder, element); err != nil {
+				return err
+			}
+		}
+		return encoder.WriteToken(jsontext.EndArray)
+	default:
+		return encoder.WriteValue(value.(jsontext.Value))
+	}
+}
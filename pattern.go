@@ -10,7 +10,7 @@ func evaluatePattern(schema *Schema, instance string) *EvaluationError {
 	if schema.compiledStringPattern == nil {
 		regExp, err := regexp.Compile(*schema.Pattern)
 		if err != nil {
-			return NewEvaluationError("pattern", "invalid_pattern", "Invalid regular expression pattern {pattern}", map[string]any{
+			return newError("pattern", CodeInvalidPattern, map[string]any{
 				"pattern": *schema.Pattern,
 			})
 		}
@@ -18,7 +18,7 @@ func evaluatePattern(schema *Schema, instance string) *EvaluationError {
 	}
 
 	if !schema.compiledStringPattern.MatchString(instance) {
-		return NewEvaluationError("pattern", "pattern_mismatch", "Value does not match the required pattern {pattern}", map[string]any{
+		return newError("pattern", CodePatternMismatch, map[string]any{
 			"pattern": *schema.Pattern,
 			"value":   instance,
 		})
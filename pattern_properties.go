@@ -80,28 +80,26 @@ func evaluatePatternProperties(
 		for i, pattern := range invalidPatterns {
 			quoted[i] = fmt.Sprintf("'%s'", pattern)
 		}
-		return results, NewEvaluationError(
-			"patternProperties", "invalid_pattern",
-			"Invalid regular expression pattern {pattern}",
+		return results, newError(
+			"patternProperties", CodeInvalidPattern,
 			map[string]any{"pattern": strings.Join(quoted, ", ")},
 		)
 	}
 
 	if len(invalidProperties) == 1 {
-		return results, NewEvaluationError(
-			"properties", "pattern_property_mismatch",
-			"Property {property} does not match the pattern schema",
+		return results, newError(
+			"properties", CodePatternPropertyMismatch,
 			map[string]any{"property": fmt.Sprintf("'%s'", invalidProperties[0])},
 		)
 	}
 	if len(invalidProperties) > 1 {
+		slices.Sort(invalidProperties)
 		quotedProperties := make([]string, len(invalidProperties))
 		for i, prop := range invalidProperties {
 			quotedProperties[i] = fmt.Sprintf("'%s'", prop)
 		}
-		return results, NewEvaluationError(
-			"properties", "pattern_properties_mismatch",
-			"Properties {properties} do not match their pattern schemas",
+		return results, newError(
+			"properties", CodePatternPropertiesMismatch,
 			map[string]any{"properties": strings.Join(quotedProperties, ", ")},
 		)
 	}
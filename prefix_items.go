@@ -47,12 +47,12 @@ func evaluatePrefixItems(
 	}
 
 	if len(invalidIndexes) == 1 {
-		return results, NewEvaluationError("prefixItems", "prefix_item_mismatch", "Item at index {index} does not match the prefixItems schema", map[string]any{
+		return results, newError("prefixItems", CodePrefixItemMismatch, map[string]any{
 			"index": invalidIndexes[0],
 		})
 	}
 	if len(invalidIndexes) > 1 {
-		return results, NewEvaluationError("prefixItems", "prefix_items_mismatch", "Items at index {indexs} do not match the prefixItems schemas", map[string]any{
+		return results, newError("prefixItems", CodePrefixItemsMismatch, map[string]any{
 			"indexs": strings.Join(invalidIndexes, ", "),
 		})
 	}
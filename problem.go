@@ -0,0 +1,77 @@
+package jsonschema
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// ProblemContentType is the media type of a [Problem] document.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 (RFC 9457) problem details document describing a
+// failed validation. Errors lists one entry per leaf error.
+type Problem struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status,omitempty"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Errors   []ProblemError `json:"errors"`
+}
+
+// ProblemError locates one validation error within the request body.
+// Pointer is a JSON Pointer in URI fragment form, such as "#/address/zip".
+type ProblemError struct {
+	Pointer string `json:"pointer"`
+	Detail  string `json:"detail"`
+	Keyword string `json:"keyword,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+// ProblemOptions configures [EvaluationResult.ToProblem].
+type ProblemOptions struct {
+	// Type is the problem type URI. Defaults to "about:blank".
+	Type string
+	// Status is the HTTP status code. Defaults to 422.
+	Status int
+	// Title defaults to the status text of Status.
+	Title string
+	// Detail is an optional human-readable explanation.
+	Detail string
+	// Instance is an optional URI identifying this occurrence.
+	Instance string
+	// Translator renders the error details; nil uses English.
+	Translator Translator
+}
+
+// ToProblem renders the result as a problem details document. A valid result
+// produces a document with an empty Errors list.
+func (e *EvaluationResult) ToProblem(opts ProblemOptions) *Problem {
+	problem := &Problem{
+		Type:     opts.Type,
+		Title:    opts.Title,
+		Status:   opts.Status,
+		Detail:   opts.Detail,
+		Instance: opts.Instance,
+		Errors:   []ProblemError{},
+	}
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+	if problem.Status == 0 {
+		problem.Status = http.StatusUnprocessableEntity
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+
+	for leaf := range e.AllErrors() {
+		problem.Errors = append(problem.Errors, ProblemError{
+			Pointer: "#" + (&url.URL{Fragment: leaf.InstanceLocation}).EscapedFragment(),
+			Detail:  leaf.Localize(opts.Translator),
+			Keyword: leaf.Keyword,
+			Code:    leaf.Code,
+		})
+	}
+	return problem
+}
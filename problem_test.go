@@ -0,0 +1,67 @@
+package jsonschema
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compileRenderSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$id": "https://example.com/schemas/user.json",
+		"type": "object",
+		"properties": {
+			"age": {"type": "integer", "minimum": 0},
+			"full name": {"type": "string"}
+		},
+		"required": ["email"]
+	}`))
+	require.NoError(t, err)
+	return schema
+}
+
+func TestToProblem(t *testing.T) {
+	result := compileRenderSchema(t).Validate(map[string]any{"age": -1, "full name": 7})
+
+	problem := result.ToProblem(ProblemOptions{Instance: "/users"})
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, http.StatusUnprocessableEntity, problem.Status)
+	assert.Equal(t, "Unprocessable Entity", problem.Title)
+	assert.Equal(t, []ProblemError{
+		{Pointer: "#", Detail: "Required property 'email' is missing", Keyword: "required", Code: "missing_required_property"},
+		{Pointer: "#/age", Detail: "-1 should be at least 0", Keyword: "minimum", Code: "value_below_minimum"},
+		{Pointer: "#/full%20name", Detail: "Value is integer but should be string", Keyword: "type", Code: "type_mismatch"},
+	}, problem.Errors)
+
+	data, err := json.Marshal(problem)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"instance":"/users"`)
+}
+
+func TestToProblemOptions(t *testing.T) {
+	result := compileRenderSchema(t).Validate(map[string]any{"email": "a", "age": -1})
+
+	problem := result.ToProblem(ProblemOptions{
+		Type:       "https://example.com/problems/invalid-user",
+		Status:     http.StatusBadRequest,
+		Title:      "Invalid user",
+		Translator: fakeTranslator{"value_below_minimum": "too small"},
+	})
+	assert.Equal(t, "https://example.com/problems/invalid-user", problem.Type)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "Invalid user", problem.Title)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "too small", problem.Errors[0].Detail)
+}
+
+func TestToProblemValidResult(t *testing.T) {
+	problem := compileRenderSchema(t).Validate(map[string]any{"email": "a"}).ToProblem(ProblemOptions{})
+
+	data, err := json.Marshal(problem)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"errors":[]`)
+}
@@ -52,9 +52,8 @@ func evaluateProperties(
 	}
 
 	if len(invalidProperties) == 1 {
-		return results, NewEvaluationError(
-			"properties", "property_mismatch",
-			"Property {property} does not match the schema",
+		return results, newError(
+			"properties", CodePropertyMismatch,
 			map[string]any{"property": fmt.Sprintf("'%s'", invalidProperties[0])},
 		)
 	}
@@ -64,9 +63,8 @@ func evaluateProperties(
 		for i, prop := range invalidProperties {
 			quotedProperties[i] = fmt.Sprintf("'%s'", prop)
 		}
-		return results, NewEvaluationError(
-			"properties", "properties_mismatch",
-			"Properties {properties} do not match their schemas",
+		return results, newError(
+			"properties", CodePropertiesMismatch,
 			map[string]any{"properties": strings.Join(quotedProperties, ", ")},
 		)
 	}
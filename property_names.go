@@ -2,6 +2,7 @@ package jsonschema
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -42,16 +43,17 @@ func evaluatePropertyNames(schema *Schema, object map[string]any, _ map[string]b
 	}
 
 	if len(invalidProperties) == 1 {
-		return results, NewEvaluationError("propertyNames", "property_name_mismatch", "Property name {property} does not match the schema", map[string]any{
+		return results, newError("propertyNames", CodePropertyNameMismatch, map[string]any{
 			"property": fmt.Sprintf("'%s'", invalidProperties[0]),
 		})
 	}
 	if len(invalidProperties) > 1 {
+		slices.Sort(invalidProperties)
 		quotedProperties := make([]string, len(invalidProperties))
 		for i, prop := range invalidProperties {
 			quotedProperties[i] = fmt.Sprintf("'%s'", prop)
 		}
-		return results, NewEvaluationError("propertyNames", "property_names_mismatch", "Property names {properties} do not match the schema", map[string]any{
+		return results, newError("propertyNames", CodePropertyNamesMismatch, map[string]any{
 			"properties": strings.Join(quotedProperties, ", "),
 		})
 	}
@@ -0,0 +1,306 @@
+package jsonschema
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultRegistryInterval is how often Registry.Watch polls by default.
+const defaultRegistryInterval = 2 * time.Second
+
+// RegistryOptions configures NewRegistry.
+type RegistryOptions struct {
+	// NewCompiler returns the compiler a load starts from, for registering
+	// formats, loaders or a lockfile. Every reload calls it for a fresh
+	// compiler. Defaults to NewCompiler.
+	NewCompiler func() *Compiler
+
+	// Interval is how often Watch checks the directory. Defaults to 2s.
+	Interval time.Duration
+
+	// OnReload, if set, is called after every reload attempted by Watch
+	// with its result.
+	OnReload func(error)
+}
+
+// Registry serves the schemas of a directory tree and reloads them when the
+// files change. Every *.json, *.yaml and *.yml file is compiled, and each
+// schema is keyed by its "$id", or by its file URL when it has none, so
+// relative "$ref"s between files without "$id" resolve too.
+//
+// A reload recompiles the changed files and the schemas that depend on them,
+// and swaps the new set in atomically only when every schema compiles and
+// every reference resolves. Otherwise the previous set stays in use and the
+// error is available from LastError.
+type Registry struct {
+	dir  string
+	opts RegistryOptions
+
+	current atomic.Pointer[registrySnapshot]
+
+	mu      sync.Mutex // Serializes reloads.
+	lastErr error
+}
+
+// registrySnapshot is an immutable set of compiled schemas.
+type registrySnapshot struct {
+	schemas map[string]*Schema       // By "$id" or file URL.
+	files   map[string]*registryFile // By path.
+}
+
+// registryFile is a schema file of a snapshot.
+type registryFile struct {
+	hash   [sha256.Size]byte
+	key    string
+	schema *Schema
+	deps   []string // Keys of the schemas this file references.
+}
+
+// NewRegistry loads the schemas in dir. It fails when any of them fails to
+// compile.
+func NewRegistry(dir string, opts RegistryOptions) (*Registry, error) {
+	if opts.NewCompiler == nil {
+		opts.NewCompiler = NewCompiler
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultRegistryInterval
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilenamePath, err)
+	}
+
+	r := &Registry{dir: absDir, opts: opts}
+	r.current.Store(&registrySnapshot{})
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Schema returns the schema with the given "$id", or file URL for schemas
+// without one.
+func (r *Registry) Schema(id string) (*Schema, bool) {
+	schema, ok := r.current.Load().schemas[id]
+	return schema, ok
+}
+
+// IDs returns the keys of the loaded schemas, sorted.
+func (r *Registry) IDs() []string {
+	return slices.Sorted(maps.Keys(r.current.Load().schemas))
+}
+
+// LastError returns the error of the last reload, or nil if it succeeded.
+func (r *Registry) LastError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastErr
+}
+
+// Watch polls the directory until ctx is done, reloading whenever a file is
+// added, removed or modified. Polling works on every platform and file
+// system, including network and container mounts.
+func (r *Registry) Watch(ctx context.Context) {
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.changed()
+			if !changed && err == nil {
+				continue
+			}
+			if err == nil {
+				err = r.Reload()
+			}
+			if r.opts.OnReload != nil {
+				r.opts.OnReload(err)
+			}
+		}
+	}
+}
+
+// changed reports whether the schema files differ from the current snapshot.
+func (r *Registry) changed() (bool, error) {
+	files, err := r.scan()
+	if err != nil {
+		return false, err
+	}
+	current := r.current.Load().files
+	if len(files) != len(current) {
+		return true, nil
+	}
+	for path, data := range files {
+		if file, ok := current[path]; !ok || file.hash != sha256.Sum256(data) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Reload rescans the directory and swaps in the new schemas if they all
+// compile. It returns, and records for LastError, the reason when they
+// do not.
+func (r *Registry) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load(r.current.Load())
+	r.lastErr = err
+	if err == nil {
+		r.current.Store(next)
+	}
+	return err
+}
+
+// load builds the next snapshot from prev, compiling only the files that
+// changed and the files depending on them.
+func (r *Registry) load(prev *registrySnapshot) (*registrySnapshot, error) {
+	contents, err := r.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	// Files whose content changed or disappeared invalidate their dependents.
+	stale := make(map[string]bool)
+	for path, file := range prev.files {
+		if data, ok := contents[path]; !ok || file.hash != sha256.Sum256(data) {
+			stale[file.key] = true
+		}
+	}
+	for _, file := range prev.files {
+		markDependents(prev.files, file.key, stale)
+	}
+
+	compiler := r.opts.NewCompiler()
+	next := &registrySnapshot{
+		schemas: make(map[string]*Schema, len(contents)),
+		files:   make(map[string]*registryFile, len(contents)),
+	}
+	batch := make(map[string][]byte)
+	for path, data := range contents {
+		if file, ok := prev.files[path]; ok && !stale[file.key] {
+			compiler.SetSchema(file.key, file.schema)
+			next.files[path] = file
+			continue
+		}
+		if isYAMLFile(path) {
+			if data, err = yamlToJSON(data); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+		batch[fileURL(path)] = data
+	}
+
+	compiled, err := compiler.CompileBatch(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for path, data := range contents {
+		file, reused := next.files[path]
+		if !reused {
+			schema := compiled[fileURL(path)]
+			if err := schema.validateRegexSyntax(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				continue
+			}
+			if unresolved := schema.UnresolvedReferenceURIs(); len(unresolved) > 0 {
+				errs = append(errs, fmt.Errorf("%s: %w: %s", path, ErrUnresolvedReference, strings.Join(unresolved, ", ")))
+				continue
+			}
+			file = &registryFile{hash: sha256.Sum256(data), key: schema.uri, schema: schema, deps: schemaDependencies(schema)}
+			next.files[path] = file
+		}
+		if other, exists := next.schemas[file.key]; exists && other != file.schema {
+			errs = append(errs, fmt.Errorf("%s: %w: %s", path, ErrDuplicateSchemaID, file.key))
+			continue
+		}
+		next.schemas[file.key] = file.schema
+	}
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+		return nil, errors.Join(errs...)
+	}
+	return next, nil
+}
+
+// scan reads the schema files under the registry directory.
+func (r *Registry) scan() (map[string][]byte, error) {
+	contents := make(map[string][]byte)
+	err := filepath.WalkDir(r.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !(strings.HasSuffix(path, ".json") || isYAMLFile(path)) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		contents[path] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDataRead, err)
+	}
+	return contents, nil
+}
+
+// markDependents marks every file referencing key, directly or not, as stale.
+func markDependents(files map[string]*registryFile, key string, stale map[string]bool) {
+	if !stale[key] {
+		return
+	}
+	for _, file := range files {
+		if !stale[file.key] && slices.Contains(file.deps, key) {
+			stale[file.key] = true
+			markDependents(files, file.key, stale)
+		}
+	}
+}
+
+// schemaDependencies returns the URIs of the other documents schema
+// references.
+func schemaDependencies(schema *Schema) []string {
+	deps := make(map[string]bool)
+	var visit func(*Schema)
+	visit = func(s *Schema) {
+		for _, resolved := range []*Schema{s.ResolvedRef, s.ResolvedDynamicRef} {
+			if resolved == nil {
+				continue
+			}
+			if uri := resolved.rootSchema().uri; uri != "" && uri != schema.uri {
+				deps[uri] = true
+			}
+		}
+		s.walkNestedSchemas(visit)
+	}
+	visit(schema)
+	return slices.Sorted(maps.Keys(deps))
+}
+
+// fileURL returns the "file" URL of an absolute path.
+func fileURL(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // C:/schemas becomes /C:/schemas
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
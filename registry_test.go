@@ -0,0 +1,116 @@
+package jsonschema
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRegistryFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func newTestRegistry(t *testing.T) (*Registry, string) {
+	t.Helper()
+	dir := t.TempDir()
+	writeRegistryFile(t, dir, "user.json", `{"$id": "https://example.com/user.json", "properties": {"name": {"$ref": "name.json"}}}`)
+	writeRegistryFile(t, dir, "name.yaml", "$id: https://example.com/name.json\ntype: string\nminLength: 2\n")
+	writeRegistryFile(t, dir, "defs/count.json", `{"type": "integer"}`)
+	writeRegistryFile(t, dir, "order.json", `{"properties": {"count": {"$ref": "defs/count.json"}}}`)
+
+	registry, err := NewRegistry(dir, RegistryOptions{Interval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	return registry, dir
+}
+
+func TestRegistryLoadsDirectoryTree(t *testing.T) {
+	registry, dir := newTestRegistry(t)
+
+	assert.Equal(t, []string{
+		fileURL(filepath.Join(dir, "defs", "count.json")),
+		fileURL(filepath.Join(dir, "order.json")),
+		"https://example.com/name.json",
+		"https://example.com/user.json",
+	}, registry.IDs())
+
+	user, ok := registry.Schema("https://example.com/user.json")
+	require.True(t, ok)
+	assert.True(t, user.Validate(map[string]any{"name": "Al"}).IsValid())
+	assert.False(t, user.Validate(map[string]any{"name": "A"}).IsValid())
+
+	order, ok := registry.Schema(fileURL(filepath.Join(dir, "order.json")))
+	require.True(t, ok)
+	assert.False(t, order.Validate(map[string]any{"count": "many"}).IsValid())
+}
+
+func TestRegistryReloadRecompilesDependents(t *testing.T) {
+	registry, dir := newTestRegistry(t)
+	userBefore, _ := registry.Schema("https://example.com/user.json")
+	orderBefore, _ := registry.Schema(fileURL(filepath.Join(dir, "order.json")))
+
+	writeRegistryFile(t, dir, "name.yaml", "$id: https://example.com/name.json\ntype: string\nminLength: 1\n")
+	require.NoError(t, registry.Reload())
+
+	user, _ := registry.Schema("https://example.com/user.json")
+	assert.NotSame(t, userBefore, user, "dependents are recompiled")
+	assert.True(t, user.Validate(map[string]any{"name": "A"}).IsValid())
+
+	order, _ := registry.Schema(fileURL(filepath.Join(dir, "order.json")))
+	assert.Same(t, orderBefore, order, "unrelated schemas are kept")
+}
+
+func TestRegistryKeepsPreviousSetOnError(t *testing.T) {
+	registry, dir := newTestRegistry(t)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "name.yaml")))
+	err := registry.Reload()
+	require.ErrorIs(t, err, ErrUnresolvedReference)
+	assert.Equal(t, err, registry.LastError())
+
+	user, ok := registry.Schema("https://example.com/user.json")
+	require.True(t, ok, "the previous set stays in use")
+	assert.False(t, user.Validate(map[string]any{"name": "A"}).IsValid())
+
+	writeRegistryFile(t, dir, "broken.json", `{"type": `)
+	writeRegistryFile(t, dir, "name.yaml", "$id: https://example.com/name.json\ntype: string\n")
+	require.Error(t, registry.Reload())
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "broken.json")))
+	require.NoError(t, registry.Reload())
+	require.NoError(t, registry.LastError())
+}
+
+func TestRegistryRejectsDuplicateIDs(t *testing.T) {
+	registry, dir := newTestRegistry(t)
+
+	writeRegistryFile(t, dir, "copy.json", `{"$id": "https://example.com/user.json"}`)
+	require.ErrorIs(t, registry.Reload(), ErrDuplicateSchemaID)
+}
+
+func TestRegistryWatch(t *testing.T) {
+	registry, dir := newTestRegistry(t)
+	reloads := make(chan error, 1)
+	registry.opts.OnReload = func(err error) { reloads <- err }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go registry.Watch(ctx)
+
+	writeRegistryFile(t, dir, "extra.json", `{"$id": "https://example.com/extra.json"}`)
+	select {
+	case err := <-reloads:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload after a file was added")
+	}
+	_, ok := registry.Schema("https://example.com/extra.json")
+	assert.True(t, ok)
+}
@@ -31,7 +31,7 @@ func evaluateRequired(schema *Schema, object map[string]any) *EvaluationError {
 
 	if len(missingProps) > 0 {
 		if len(missingProps) == 1 {
-			return NewEvaluationError("required", "missing_required_property", "Required property {property} is missing", map[string]any{
+			return newError("required", CodeMissingRequiredProperty, map[string]any{
 				"property": fmt.Sprintf("'%s'", missingProps[0]),
 			})
 		}
@@ -39,7 +39,7 @@ func evaluateRequired(schema *Schema, object map[string]any) *EvaluationError {
 		for i, prop := range missingProps {
 			quotedProperties[i] = fmt.Sprintf("'%s'", prop)
 		}
-		return NewEvaluationError("required", "missing_required_properties", "Required properties {properties} are missing", map[string]any{
+		return newError("required", CodeMissingRequiredProperties, map[string]any{
 			"properties": strings.Join(quotedProperties, ", "),
 		})
 	}
@@ -7,12 +7,29 @@ type Translator interface {
 	Translate(code string, params map[string]any) (message string, ok bool)
 }
 
+// LocaleTranslator is implemented by translators bound to a single locale.
+// Localize uses the locale to pick variants of custom messages declared with
+// the "errorMessage" keyword.
+type LocaleTranslator interface {
+	Translator
+	Locale() string
+}
+
 // EvaluationError represents an error that occurred during schema evaluation
 type EvaluationError struct {
 	Keyword string         `json:"keyword"`
 	Code    string         `json:"code"`
 	Message string         `json:"message"`
 	Params  map[string]any `json:"params"`
+	// Messages holds the custom message declared by the "errorMessage"
+	// keyword, with its locale variants. It is nil for built-in messages.
+	Messages LocalizedMessage `json:"messages,omitempty"`
+	// EvaluationPath, SchemaLocation and InstanceLocation locate the error.
+	// They are set on leaf errors, such as those returned by
+	// EvaluationResult.AllErrors and EvaluationResult.Err, and empty otherwise.
+	EvaluationPath   string `json:"evaluationPath,omitempty"`
+	SchemaLocation   string `json:"schemaLocation,omitempty"`
+	InstanceLocation string `json:"instanceLocation,omitempty"`
 }
 
 // NewEvaluationError creates a new evaluation error with the specified details
@@ -32,15 +49,28 @@ func NewEvaluationError(keyword string, code string, message string, params ...m
 	}
 }
 
-// Error returns a string representation of the evaluation error.
+// Error returns a string representation of the evaluation error. Custom
+// messages are returned as rendered by the "errorMessage" keyword.
 func (e *EvaluationError) Error() string {
+	if e.Messages != nil {
+		return e.Message
+	}
 	return replace(e.Message, e.Params)
 }
 
 // Localize returns a localized error message using the provided translator.
 // A nil translator or a missing translation falls back to the built-in
-// English message; localization never fails.
+// English message; localization never fails. Custom messages from the
+// "errorMessage" keyword take precedence and pick the variant matching the
+// translator's locale when it implements LocaleTranslator.
 func (e *EvaluationError) Localize(t Translator) string {
+	if e.Messages != nil {
+		var locale string
+		if lt, ok := t.(LocaleTranslator); ok {
+			locale = lt.Locale()
+		}
+		return e.Messages.ForLocale(locale)
+	}
 	if t != nil {
 		if message, ok := t.Translate(e.Code, e.Params); ok {
 			return message
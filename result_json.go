@@ -0,0 +1,63 @@
+package jsonschema
+
+import "github.com/go-json-experiment/json"
+
+// Evaluation results round-trip through their own JSON output: numbers in
+// params and annotations decode as encoding/json.Number, so exact values
+// survive, and maps are written in sorted key order for stable output.
+
+// MarshalJSON encodes the error, writing json.Number params as numbers.
+func (e *EvaluationError) MarshalJSON() ([]byte, error) {
+	type Alias EvaluationError
+	return marshalJSON((*Alias)(e), json.Deterministic(true))
+}
+
+// UnmarshalJSON decodes an error written by MarshalJSON.
+func (e *EvaluationError) UnmarshalJSON(data []byte) error {
+	type Alias EvaluationError
+	var alias Alias
+	if err := unmarshalJSON(data, &alias); err != nil {
+		return err
+	}
+	if len(alias.Params) == 0 {
+		alias.Params = nil
+	}
+	*e = EvaluationError(alias)
+	return nil
+}
+
+// MarshalJSON encodes the result tree in the hierarchical output format.
+func (e *EvaluationResult) MarshalJSON() ([]byte, error) {
+	type Alias EvaluationResult
+	return marshalJSON((*Alias)(e), json.Deterministic(true))
+}
+
+// UnmarshalJSON decodes a result written by MarshalJSON. The decoded result
+// supports the same queries and rendering as the original, such as
+// DetailedErrors, ToLocalizedList and Err, but is not tied to a schema.
+func (e *EvaluationResult) UnmarshalJSON(data []byte) error {
+	type Alias EvaluationResult
+	var alias Alias
+	if err := unmarshalJSON(data, &alias); err != nil {
+		return err
+	}
+	*e = EvaluationResult(alias)
+	return nil
+}
+
+// MarshalJSON encodes the list output format.
+func (l List) MarshalJSON() ([]byte, error) {
+	type Alias List
+	return marshalJSON(Alias(l), json.Deterministic(true))
+}
+
+// UnmarshalJSON decodes a list written by MarshalJSON.
+func (l *List) UnmarshalJSON(data []byte) error {
+	type Alias List
+	var alias Alias
+	if err := unmarshalJSON(data, &alias); err != nil {
+		return err
+	}
+	*l = List(alias)
+	return nil
+}
@@ -0,0 +1,98 @@
+package jsonschema
+
+import (
+	stdjson "encoding/json"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func roundTripResultSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := NewCompiler().Compile([]byte(`{
+		"title": "order",
+		"type": "object",
+		"properties": {
+			"id": {"enum": [1, 2]},
+			"total": {"type": "number", "maximum": 100.25, "default": 0.10},
+			"items": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}
+		},
+		"required": ["id", "customer"]
+	}`))
+	require.NoError(t, err)
+	return schema
+}
+
+func TestEvaluationResultJSONRoundTrip(t *testing.T) {
+	schema := roundTripResultSchema(t)
+	result := schema.ValidateJSON([]byte(`{"id": 18446744073709551615, "total": 100.5, "items": ["a", 1, "a"]}`))
+	require.False(t, result.IsValid())
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var decoded EvaluationResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	again, err := json.Marshal(&decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+	assert.Equal(t, string(data), string(again), "output is deterministic")
+
+	assert.Equal(t, result.DetailedErrors(), decoded.DetailedErrors())
+	assert.Equal(t, result.ToList(false).Errors, decoded.ToList(false).Errors)
+	assert.Equal(t, result.Err().Error(), decoded.Err().Error())
+	assert.ErrorIs(t, decoded.Err(), CodeValueNotInEnum)
+
+	enumErrors := decoded.ErrorsAt("/id")
+	require.Len(t, enumErrors, 1)
+	assert.Equal(t, stdjson.Number("18446744073709551615"), enumErrors[0].Params["received"])
+	assert.Equal(t, "value_not_in_enum", enumErrors[0].Code)
+	assert.Equal(t, "translated", enumErrors[0].Localize(fakeTranslator{"value_not_in_enum": "translated"}))
+
+	assert.Contains(t, string(data), `"received":18446744073709551615`, "numbers are written exactly")
+}
+
+func TestEvaluationResultJSONKeepsAnnotationsAndOrder(t *testing.T) {
+	schema := roundTripResultSchema(t)
+	result := schema.ValidateJSON([]byte(`{"id": 1, "customer": "c", "total": 1}`))
+	require.True(t, result.IsValid())
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var decoded EvaluationResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "order", decoded.Annotations["title"])
+	require.Len(t, decoded.Details, len(result.Details))
+	for i, detail := range result.Details {
+		assert.Equal(t, detail.EvaluationPath, decoded.Details[i].EvaluationPath)
+	}
+
+	var total *EvaluationResult
+	for _, detail := range decoded.Details {
+		if detail.InstanceLocation == "/total" {
+			total = detail
+		}
+	}
+	require.NotNil(t, total)
+	assert.Equal(t, stdjson.Number("0.10"), total.Annotations["default"])
+}
+
+func TestListJSONRoundTrip(t *testing.T) {
+	schema := roundTripResultSchema(t)
+	list := schema.ValidateJSON([]byte(`{"id": 3, "items": [1]}`)).ToList()
+
+	data, err := json.Marshal(list)
+	require.NoError(t, err)
+
+	var decoded List
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	again, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+	assert.Equal(t, list.Errors, decoded.Errors)
+	require.Len(t, decoded.Details, len(list.Details))
+}
@@ -0,0 +1,61 @@
+package jsonschema
+
+import (
+	"iter"
+	"strings"
+)
+
+// AllErrors yields the leaf errors of the result in document order: by
+// instance location, with array indexes compared numerically, then by
+// evaluation path and keyword. Summary errors such as "properties" are
+// replaced by the errors of the children they report on. Each yielded error
+// is a copy carrying its absolute evaluation path, schema location and
+// instance location.
+func (e *EvaluationResult) AllErrors() iter.Seq[*EvaluationError] {
+	return func(yield func(*EvaluationError) bool) {
+		if e == nil || e.Valid {
+			return
+		}
+		for _, leaf := range e.leafErrors() {
+			if !yield(leaf) {
+				return
+			}
+		}
+	}
+}
+
+// ErrorsByLocation yields the leaf errors of AllErrors paired with their
+// instance location, a JSON Pointer into the validated instance.
+func (e *EvaluationResult) ErrorsByLocation() iter.Seq2[string, *EvaluationError] {
+	return func(yield func(string, *EvaluationError) bool) {
+		for leaf := range e.AllErrors() {
+			if !yield(leaf.InstanceLocation, leaf) {
+				return
+			}
+		}
+	}
+}
+
+// ErrorsAt returns the leaf errors reported for the instance location
+// pointer, such as "/address/zip". The empty pointer selects the root.
+func (e *EvaluationResult) ErrorsAt(pointer string) []*EvaluationError {
+	var errs []*EvaluationError
+	for location, leaf := range e.ErrorsByLocation() {
+		if location == pointer {
+			errs = append(errs, leaf)
+		}
+	}
+	return errs
+}
+
+// ErrorsUnder returns the leaf errors reported for pointer and every
+// location below it. The empty pointer selects all errors.
+func (e *EvaluationResult) ErrorsUnder(pointer string) []*EvaluationError {
+	var errs []*EvaluationError
+	for location, leaf := range e.ErrorsByLocation() {
+		if pointer == "" || location == pointer || strings.HasPrefix(location, pointer+"/") {
+			errs = append(errs, leaf)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compileOrderSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "pattern": "^[A-Z]"},
+			"address": {
+				"type": "object",
+				"properties": {"zip": {"type": "string"}, "city": {"type": "string"}}
+			},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		},
+		"additionalProperties": {"type": "integer"},
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+	return schema
+}
+
+func orderInstance() map[string]any {
+	tags := make([]any, 12)
+	for i := range tags {
+		tags[i] = "ok"
+	}
+	tags[2], tags[10] = 2, 10
+	return map[string]any{
+		"name":    "a",
+		"address": map[string]any{"zip": 1, "city": 2},
+		"tags":    tags,
+		"a/b":     "x",
+	}
+}
+
+func TestAllErrorsDocumentOrder(t *testing.T) {
+	schema := compileOrderSchema(t)
+	result := schema.Validate(orderInstance())
+
+	var locations []string
+	for leaf := range result.AllErrors() {
+		assert.NotEmpty(t, leaf.EvaluationPath)
+		assert.NotEmpty(t, leaf.SchemaLocation)
+		locations = append(locations, leaf.InstanceLocation+" "+leaf.Keyword)
+	}
+	assert.Equal(t, []string{
+		" required",
+		"/a~1b type",
+		"/address/city type",
+		"/address/zip type",
+		"/name minLength",
+		"/name pattern",
+		"/tags/2 type",
+		"/tags/10 type",
+	}, locations)
+
+	for range 20 {
+		again := slices.Collect(schema.Validate(orderInstance()).AllErrors())
+		require.Len(t, again, len(locations))
+		for i, leaf := range again {
+			assert.Equal(t, locations[i], leaf.InstanceLocation+" "+leaf.Keyword)
+		}
+	}
+}
+
+func TestAllErrorsStopsEarly(t *testing.T) {
+	result := compileOrderSchema(t).Validate(orderInstance())
+
+	count := 0
+	for range result.AllErrors() {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestErrorsAtAndUnder(t *testing.T) {
+	result := compileOrderSchema(t).Validate(orderInstance())
+
+	at := result.ErrorsAt("/name")
+	require.Len(t, at, 2)
+	assert.Equal(t, "string_too_short", at[0].Code)
+	assert.Equal(t, "pattern_mismatch", at[1].Code)
+
+	assert.Len(t, result.ErrorsAt(""), 1, "root errors only")
+	assert.Empty(t, result.ErrorsAt("/address"), "errors below are not at the location")
+	assert.Len(t, result.ErrorsUnder("/address"), 2)
+	assert.Len(t, result.ErrorsUnder("/tags"), 2)
+	assert.Empty(t, result.ErrorsUnder("/tag"), "prefix must end at a token boundary")
+	assert.Len(t, result.ErrorsUnder(""), 8)
+}
+
+func TestErrorsForValidResult(t *testing.T) {
+	result := compileOrderSchema(t).Validate(map[string]any{"id": 1})
+	require.True(t, result.IsValid())
+
+	assert.Empty(t, slices.Collect(result.AllErrors()))
+	assert.Empty(t, result.ErrorsUnder(""))
+}
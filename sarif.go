@@ -0,0 +1,241 @@
+package jsonschema
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// SARIF 2.1.0 identifiers written by [EvaluationResult.ToSARIF].
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFLog is a SARIF 2.1.0 log with a single run, the subset of the format
+// used to report validation errors to code-scanning tools.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun holds the tool description and the reported results.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool that produced a run.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver names the tool and lists one rule per reported error code.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes an error code.
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+// SARIFResult reports one leaf validation error.
+type SARIFResult struct {
+	RuleID           string          `json:"ruleId"`
+	RuleIndex        int             `json:"ruleIndex"`
+	Level            string          `json:"level"`
+	Message          SARIFMessage    `json:"message"`
+	Locations        []SARIFLocation `json:"locations"`
+	RelatedLocations []SARIFLocation `json:"relatedLocations,omitempty"`
+	Properties       map[string]any  `json:"properties,omitempty"`
+}
+
+// SARIFLocation points at the validated document or the schema.
+type SARIFLocation struct {
+	ID               int                    `json:"id,omitempty"`
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+	Message          *SARIFMessage          `json:"message,omitempty"`
+}
+
+// SARIFPhysicalLocation is a file and an optional region within it.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation identifies a file by URI.
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a 1-based line and column; columns count UTF-16 code units
+// as SARIF requires by default.
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// SARIFLogicalLocation names the failing value by its JSON Pointer.
+type SARIFLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+// SARIFMessage is a plain-text message.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFOptions configures [EvaluationResult.ToSARIF].
+type SARIFOptions struct {
+	// ArtifactURI is the URI of the validated file.
+	ArtifactURI string
+	// Source is the validated JSON document. When set, results carry the
+	// line and column of the failing value.
+	Source []byte
+	// SchemaURI is the URI of the schema file. Defaults to the schema
+	// location recorded in the result.
+	SchemaURI string
+	// ToolName defaults to "jsonschema".
+	ToolName string
+	// ToolVersion is optional.
+	ToolVersion string
+	// Translator renders the messages; nil uses English.
+	Translator Translator
+}
+
+// ToSARIF renders the result as a SARIF 2.1.0 log. Each leaf error becomes a
+// result whose rule is its error code, located in the validated file and
+// related to the schema keyword that reported it.
+func (e *EvaluationResult) ToSARIF(opts SARIFOptions) *SARIFLog {
+	driver := SARIFDriver{
+		Name:           opts.ToolName,
+		Version:        opts.ToolVersion,
+		InformationURI: "https://github.com/kaptinlin/jsonschema",
+		Rules:          []SARIFRule{},
+	}
+	if driver.Name == "" {
+		driver.Name = "jsonschema"
+	}
+
+	var positions map[string]SARIFRegion
+	if opts.Source != nil {
+		positions = sourceRegions(opts.Source)
+	}
+
+	ruleIndex := make(map[string]int)
+	results := []SARIFResult{}
+	for leaf := range e.AllErrors() {
+		index, ok := ruleIndex[leaf.Code]
+		if !ok {
+			index = len(driver.Rules)
+			ruleIndex[leaf.Code] = index
+			description := errorCodeIndex[ErrorCode(leaf.Code)].Template
+			if description == "" {
+				description = leaf.Code
+			}
+			driver.Rules = append(driver.Rules, SARIFRule{ID: leaf.Code, ShortDescription: SARIFMessage{Text: description}})
+		}
+
+		location := SARIFLocation{
+			PhysicalLocation: &SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: opts.ArtifactURI}},
+			LogicalLocations: []SARIFLogicalLocation{{FullyQualifiedName: leaf.InstanceLocation, Kind: "member"}},
+		}
+		if region, ok := positions[leaf.InstanceLocation]; ok {
+			location.PhysicalLocation.Region = &region
+		}
+
+		result := SARIFResult{
+			RuleID:    leaf.Code,
+			RuleIndex: index,
+			Level:     "error",
+			Message:   SARIFMessage{Text: leaf.Localize(opts.Translator)},
+			Locations: []SARIFLocation{location},
+			Properties: map[string]any{
+				"keyword":        leaf.Keyword,
+				"evaluationPath": leaf.EvaluationPath,
+				"schemaLocation": leaf.SchemaLocation,
+			},
+		}
+		if schemaURI := sarifSchemaURI(opts.SchemaURI, leaf.SchemaLocation); schemaURI != "" {
+			result.RelatedLocations = []SARIFLocation{{
+				ID:               1,
+				PhysicalLocation: &SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: schemaURI}},
+				Message:          &SARIFMessage{Text: "Schema keyword " + leaf.Keyword + " at " + leaf.SchemaLocation},
+			}}
+		}
+		results = append(results, result)
+	}
+
+	return &SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs:    []SARIFRun{{Tool: SARIFTool{Driver: driver}, Results: results}},
+	}
+}
+
+// sarifSchemaURI returns the configured schema URI or the document part of
+// the recorded schema location.
+func sarifSchemaURI(configured, schemaLocation string) string {
+	if configured != "" {
+		return configured
+	}
+	uri, _, _ := strings.Cut(schemaLocation, "#")
+	return uri
+}
+
+// sourceRegions maps the JSON Pointer of every value in src to the line and
+// column where the value starts. Invalid JSON yields the regions read so far.
+func sourceRegions(src []byte) map[string]SARIFRegion {
+	regions := make(map[string]SARIFRegion)
+	lineStarts := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+
+	dec := jsontext.NewDecoder(bytes.NewReader(src))
+	for {
+		offset := int(dec.InputOffset())
+		token, err := dec.ReadToken()
+		if err != nil {
+			return regions
+		}
+		switch token.Kind() {
+		case '}', ']':
+			continue
+		case '{', '[':
+		default:
+			if kind, length := dec.StackIndex(dec.StackDepth()); kind == '{' && length%2 == 1 {
+				continue // object member name
+			}
+		}
+
+		for offset < len(src) && strings.IndexByte(" \t\r\n,:", src[offset]) >= 0 {
+			offset++
+		}
+		line, _ := slices.BinarySearch(lineStarts, offset+1)
+		lineStart := lineStarts[line-1]
+		column := 1
+		for _, r := range string(src[lineStart:offset]) {
+			if r == utf8.RuneError {
+				column++
+				continue
+			}
+			column += utf16.RuneLen(r)
+		}
+		regions[string(dec.StackPointer())] = SARIFRegion{StartLine: line, StartColumn: column}
+	}
+}
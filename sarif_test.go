@@ -0,0 +1,73 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSARIF(t *testing.T) {
+	source := []byte("{\n  \"age\": -1,\n  \"full name\": [\"é\", 7]\n}")
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$id": "https://example.com/schemas/user.json",
+		"properties": {
+			"age": {"minimum": 0},
+			"full name": {"items": {"type": "string"}}
+		},
+		"required": ["email"]
+	}`))
+	require.NoError(t, err)
+
+	log := schema.ValidateJSON(source).ToSARIF(SARIFOptions{
+		ArtifactURI: "data/user.json",
+		Source:      source,
+		ToolVersion: "1.0.0",
+	})
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "jsonschema", run.Tool.Driver.Name)
+	assert.Equal(t, "1.0.0", run.Tool.Driver.Version)
+
+	require.Len(t, run.Results, 3)
+	regions := make(map[string]*SARIFRegion)
+	for _, result := range run.Results {
+		assert.Equal(t, "error", result.Level)
+		assert.Equal(t, result.RuleID, run.Tool.Driver.Rules[result.RuleIndex].ID)
+		location := result.Locations[0]
+		assert.Equal(t, "data/user.json", location.PhysicalLocation.ArtifactLocation.URI)
+		regions[location.LogicalLocations[0].FullyQualifiedName] = location.PhysicalLocation.Region
+		require.Len(t, result.RelatedLocations, 1)
+		assert.Equal(t, "https://example.com/schemas/user.json",
+			result.RelatedLocations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+	assert.Equal(t, &SARIFRegion{StartLine: 1, StartColumn: 1}, regions[""])
+	assert.Equal(t, &SARIFRegion{StartLine: 2, StartColumn: 10}, regions["/age"])
+	assert.Equal(t, &SARIFRegion{StartLine: 3, StartColumn: 22}, regions["/full name/1"])
+
+	assert.Equal(t, "Value is integer but should be string", run.Results[2].Message.Text)
+	assert.Equal(t, "Value is {received} but should be {expected}",
+		run.Tool.Driver.Rules[run.Results[2].RuleIndex].ShortDescription.Text)
+
+	data, err := json.Marshal(log)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"$schema":"https://json.schemastore.org/sarif-2.1.0.json"`)
+}
+
+func TestToSARIFWithoutSource(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"type": "string"}`))
+	require.NoError(t, err)
+
+	log := schema.Validate(1).ToSARIF(SARIFOptions{
+		ArtifactURI: "value.json",
+		SchemaURI:   "schemas/value.json",
+		Translator:  fakeTranslator{"type_mismatch": "wrong type"},
+	})
+	require.Len(t, log.Runs[0].Results, 1)
+	result := log.Runs[0].Results[0]
+	assert.Nil(t, result.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "wrong type", result.Message.Text)
+	assert.Equal(t, "schemas/value.json", result.RelatedLocations[0].PhysicalLocation.ArtifactLocation.URI)
+}
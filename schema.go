@@ -30,7 +30,11 @@ type Schema struct {
 	rawExtra               map[string]jsontext.Value // Members not bound to a typed field; dialect layer claims known ones, the rest become Extra.
 	legacyExclusiveMinimum jsontext.Value            // Raw Draft-04 boolean exclusiveMinimum value.
 	legacyExclusiveMaximum jsontext.Value            // Raw Draft-04 boolean exclusiveMaximum value.
+	legacyType             jsontext.Value            // Raw Draft-03 type union listing schemas.
+	legacyRequired         jsontext.Value            // Raw Draft-03 boolean required value.
 	disableValidation      bool                      // True when the active metaschema omits validation vocabulary.
+	strictViolations       []StrictViolation         // Findings of strict mode, see StrictModeWarn.
+	source                 jsontext.Value            // Document a root schema was compiled from, embedded as is by Bundle.
 
 	ID     string  `json:"$id,omitempty"`     // Public identifier for the schema.
 	Schema string  `json:"$schema,omitempty"` // URI indicating the specification the schema conforms to.
@@ -54,24 +58,24 @@ type Schema struct {
 	AllOf []*Schema `json:"allOf,omitempty"` // Array of schemas for validating the instance against all of them.
 	AnyOf []*Schema `json:"anyOf,omitempty"` // Array of schemas for validating the instance against any of them.
 	OneOf []*Schema `json:"oneOf,omitempty"` // Array of schemas for validating the instance against exactly one of them.
-	Not   *Schema   `json:"not,omitempty"`   // Schema for validating the instance against the negation of it.
+	Not   *Schema   `json:"not,omitzero"`    // Schema for validating the instance against the negation of it.
 
 	// Applying subschemas conditionally, see https://json-schema.org/draft/2020-12/json-schema-core#name-keywords-for-applying-subsche
-	If               *Schema            `json:"if,omitempty"`               // Schema to be evaluated as a condition
-	Then             *Schema            `json:"then,omitempty"`             // Schema to be evaluated if 'if' is successful
-	Else             *Schema            `json:"else,omitempty"`             // Schema to be evaluated if 'if' is not successful
+	If               *Schema            `json:"if,omitzero"`                // Schema to be evaluated as a condition
+	Then             *Schema            `json:"then,omitzero"`              // Schema to be evaluated if 'if' is successful
+	Else             *Schema            `json:"else,omitzero"`              // Schema to be evaluated if 'if' is not successful
 	DependentSchemas map[string]*Schema `json:"dependentSchemas,omitempty"` // Dependent schemas based on property presence
 
 	// Applying subschemas to array keywords, see https://json-schema.org/draft/2020-12/json-schema-core#name-keywords-for-applying-subschem
 	PrefixItems []*Schema `json:"prefixItems,omitempty"` // Array of schemas for validating the array items' prefix.
-	Items       *Schema   `json:"items,omitempty"`       // Schema for items in an array.
-	Contains    *Schema   `json:"contains,omitempty"`    // Schema for validating items in the array.
+	Items       *Schema   `json:"items,omitzero"`        // Schema for items in an array.
+	Contains    *Schema   `json:"contains,omitzero"`     // Schema for validating items in the array.
 
 	// Applying subschemas to objects keywords, see https://json-schema.org/draft/2020-12/json-schema-core#name-keywords-for-applying-subschemas
-	Properties           *SchemaMap `json:"properties,omitempty"`           // Definitions of properties for object types.
-	PatternProperties    *SchemaMap `json:"patternProperties,omitempty"`    // Definitions of properties for object types matched by specific patterns.
-	AdditionalProperties *Schema    `json:"additionalProperties,omitempty"` // Can be a boolean or a schema, controls additional properties handling.
-	PropertyNames        *Schema    `json:"propertyNames,omitempty"`        // Can be a boolean or a schema, controls property names validation.
+	Properties           *SchemaMap `json:"properties,omitempty"`          // Definitions of properties for object types.
+	PatternProperties    *SchemaMap `json:"patternProperties,omitempty"`   // Definitions of properties for object types matched by specific patterns.
+	AdditionalProperties *Schema    `json:"additionalProperties,omitzero"` // Can be a boolean or a schema, controls additional properties handling.
+	PropertyNames        *Schema    `json:"propertyNames,omitzero"`        // Can be a boolean or a schema, controls property names validation.
 
 	// Any validation keywords, see https://json-schema.org/draft/2020-12/json-schema-validation#section-6.1
 	Type  SchemaType  `json:"type,omitempty"`  // Can be a single type or an array of types.
@@ -98,7 +102,7 @@ type Schema struct {
 	MinContains *float64 `json:"minContains,omitempty"` // Minimum number of items in the array that must match the contains schema.
 
 	// https://json-schema.org/draft/2020-12/json-schema-core#name-unevaluateditems
-	UnevaluatedItems *Schema `json:"unevaluatedItems,omitempty"` // Schema for unevaluated items in an array.
+	UnevaluatedItems *Schema `json:"unevaluatedItems,omitzero"` // Schema for unevaluated items in an array.
 
 	// Object validation keywords, see https://json-schema.org/draft/2020-12/json-schema-validation#section-6.5
 	MaxProperties     *float64            `json:"maxProperties,omitempty"`     // Maximum number of properties in an object.
@@ -107,12 +111,12 @@ type Schema struct {
 	DependentRequired map[string][]string `json:"dependentRequired,omitempty"` // Properties required when another property is present.
 
 	// https://json-schema.org/draft/2020-12/json-schema-core#name-unevaluatedproperties
-	UnevaluatedProperties *Schema `json:"unevaluatedProperties,omitempty"` // Schema for unevaluated properties in an object.
+	UnevaluatedProperties *Schema `json:"unevaluatedProperties,omitzero"` // Schema for unevaluated properties in an object.
 
 	// Content validation keywords, see https://json-schema.org/draft/2020-12/json-schema-validation#name-a-vocabulary-for-the-conten
 	ContentEncoding  *string `json:"contentEncoding,omitempty"`  // Encoding format of the content.
 	ContentMediaType *string `json:"contentMediaType,omitempty"` // Media type of the content.
-	ContentSchema    *Schema `json:"contentSchema,omitempty"`    // Schema for validating the content.
+	ContentSchema    *Schema `json:"contentSchema,omitzero"`     // Schema for validating the content.
 
 	// Meta-data for schema and instance description, see https://json-schema.org/draft/2020-12/json-schema-validation#name-a-vocabulary-for-basic-meta
 	Title       *string `json:"title,omitempty"`       // A short summary of the schema.
@@ -123,6 +127,9 @@ type Schema struct {
 	WriteOnly   *bool   `json:"writeOnly,omitempty"`   // Indicates that the property is write-only.
 	Examples    []any   `json:"examples,omitempty"`    // Examples of the instance data that validates against this schema.
 
+	// Custom error messages, compatible with ajv-errors, see https://github.com/ajv-validator/ajv-errors
+	ErrorMessage *ErrorMessage `json:"errorMessage,omitempty"` // Messages replacing the built-in validation errors.
+
 	// Extra keywords not in specification
 	Extra map[string]any `json:"-"`
 }
@@ -176,8 +183,10 @@ func (s *Schema) initializeSchemaCore(compiler *Compiler, parent *Schema, resolv
 	if s.Anchor != "" {
 		s.setAnchor(s.Anchor)
 	}
-	if s.Dialect().supportsLegacyIDAnchors() && strings.HasPrefix(s.ID, "#") {
-		s.setLegacyIDAnchor(strings.TrimPrefix(s.ID, "#"))
+	if s.Dialect().supportsLegacyIDAnchors() {
+		if _, fragment, ok := strings.Cut(s.ID, "#"); ok && fragment != "" && !strings.HasPrefix(fragment, "/") {
+			s.setLegacyIDAnchor(fragment)
+		}
 	}
 	if s.DynamicAnchor != "" {
 		s.setDynamicAnchor(s.DynamicAnchor)
@@ -577,6 +586,9 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		Items            jsontext.Value `json:"items,omitempty"`
 		ExclusiveMinimum jsontext.Value `json:"exclusiveMinimum,omitempty"`
 		ExclusiveMaximum jsontext.Value `json:"exclusiveMaximum,omitempty"`
+		// Draft-03 allows schemas in "type" and a boolean "required".
+		Type     jsontext.Value `json:"type,omitempty"`
+		Required jsontext.Value `json:"required,omitempty"`
 		// Const is captured as a raw token so "const": null is preserved
 		// (a *ConstValue field would be niled by the decoder, losing IsSet).
 		Const jsontext.Value            `json:"const,omitempty"`
@@ -595,6 +607,12 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 	if err := decodeExclusiveBound("exclusiveMaximum", aux.ExclusiveMaximum, &s.ExclusiveMaximum, &s.legacyExclusiveMaximum); err != nil {
 		return err
 	}
+	if err := decodeType(aux.Type, &s.Type, &s.legacyType); err != nil {
+		return err
+	}
+	if err := decodeRequired(aux.Required, &s.Required, &s.legacyRequired); err != nil {
+		return err
+	}
 	// "items" polymorphism (legacy tuple form vs 2020-12 list form). When items
 	// is an array, the sibling "additionalItems" (legacy) validates the rest;
 	// consume it from Rest so it is not later treated as an extension keyword.
@@ -0,0 +1,162 @@
+package jsonschema
+
+import (
+	"container/list"
+	"slices"
+	"weak"
+)
+
+// SetSchemaCacheLimit bounds the number of schemas the compiler keeps by
+// URI. Once the limit is exceeded, the least recently compiled or referenced
+// schemas are evicted as if removed with RemoveSchema. Zero, the default,
+// means no limit.
+//
+// Evicted schemas keep working for callers holding them; a later reference
+// to their URI loads and compiles them again.
+func (c *Compiler) SetSchemaCacheLimit(limit int) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cacheLimit = max(limit, 0)
+	c.evictLocked()
+	return c
+}
+
+// RemoveSchema removes the schema cached as uri, together with the cached
+// schemas whose references resolve to it, so no cached schema is left
+// pointing at a schema the compiler no longer knows. Schemas waiting for
+// other URIs to be compiled stop waiting. Removing a URI that is not cached
+// does nothing.
+func (c *Compiler) RemoveSchema(uri string) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeLocked(uri)
+	return c
+}
+
+// cachedSchema returns the schema compiled as uri by c or its parents, and
+// marks it as recently used.
+func (c *Compiler) cachedSchema(uri string) (*Schema, bool) {
+	for ; c != nil; c = c.parent {
+		c.mu.Lock()
+		schema, ok := c.schemas[uri]
+		if ok {
+			c.touchLocked(uri)
+		}
+		c.mu.Unlock()
+		if ok {
+			return schema, true
+		}
+	}
+	return nil, false
+}
+
+// storeLocked caches schema as uri. Callers evict afterwards, once the
+// schemas compiled together are resolved.
+func (c *Compiler) storeLocked(uri string, schema *Schema) {
+	c.schemas[uri] = schema
+	c.touchLocked(uri)
+}
+
+// touchLocked marks uri as the most recently used schema.
+func (c *Compiler) touchLocked(uri string) {
+	if c.recency == nil {
+		c.recency = list.New()
+		c.recencyIndex = make(map[string]*list.Element)
+	}
+	if element, ok := c.recencyIndex[uri]; ok {
+		c.recency.MoveToFront(element)
+		return
+	}
+	c.recencyIndex[uri] = c.recency.PushFront(uri)
+}
+
+// evict removes least recently used schemas until the cache limit is met.
+func (c *Compiler) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictLocked()
+}
+
+func (c *Compiler) evictLocked() {
+	for c.cacheLimit > 0 && len(c.schemas) > c.cacheLimit {
+		oldest := c.recency.Back()
+		if oldest == nil {
+			return
+		}
+		c.removeLocked(oldest.Value.(string))
+	}
+}
+
+// discard removes schema from the cache after it failed a check that needs
+// it compiled, so that neither Schema nor later references reach it. Only
+// schemas the failing call compiled are discarded, never ones it found
+// cached.
+func (c *Compiler) discard(schema *Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if schema.uri != "" && c.schemas[schema.uri] == schema {
+		c.removeLocked(schema.uri)
+	}
+	c.anonymous = slices.DeleteFunc(c.anonymous, func(pointer weak.Pointer[Schema]) bool {
+		return pointer.Value() == schema
+	})
+}
+
+// removeLocked removes uri from the cache and, once its schema is no longer
+// cached under any URI, the cached schemas referencing it.
+func (c *Compiler) removeLocked(uri string) {
+	target, ok := c.schemas[uri]
+	delete(c.schemas, uri)
+	if element, found := c.recencyIndex[uri]; found {
+		c.recency.Remove(element)
+		delete(c.recencyIndex, uri)
+	}
+	if !ok {
+		return
+	}
+	for _, schema := range c.schemas {
+		if schema == target {
+			return
+		}
+	}
+
+	for waitingFor, waiting := range c.unresolvedRefs {
+		waiting = slices.DeleteFunc(waiting, func(s *Schema) bool { return s == target })
+		if len(waiting) == 0 {
+			delete(c.unresolvedRefs, waitingFor)
+		} else {
+			c.unresolvedRefs[waitingFor] = waiting
+		}
+	}
+
+	var dependents []string
+	for other, schema := range c.schemas {
+		if referencesSchema(schema, target) {
+			dependents = append(dependents, other)
+		}
+	}
+	for _, other := range dependents {
+		c.removeLocked(other)
+	}
+}
+
+// referencesSchema reports whether a reference within s resolves to target
+// or one of its subschemas.
+func referencesSchema(s, target *Schema) bool {
+	found := false
+	var visit func(*Schema)
+	visit = func(s *Schema) {
+		for _, resolved := range []*Schema{s.ResolvedRef, s.ResolvedDynamicRef} {
+			for ; resolved != nil; resolved = resolved.parent {
+				if resolved == target {
+					found = true
+				}
+			}
+		}
+		if !found {
+			s.walkNestedSchemas(visit)
+		}
+	}
+	visit(s)
+	return found
+}
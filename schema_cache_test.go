@@ -0,0 +1,97 @@
+package jsonschema
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cachedURIs(c *Compiler) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var uris []string
+	for uri := range c.schemas {
+		uris = append(uris, uri)
+	}
+	slices.Sort(uris)
+	return uris
+}
+
+func TestRemoveSchema(t *testing.T) {
+	compiler := NewCompiler()
+	delete(compiler.Loaders, "https")
+	for _, source := range []string{
+		`{"$id": "https://example.com/name.json", "type": "string"}`,
+		`{"$id": "https://example.com/user.json", "properties": {"name": {"$ref": "name.json"}}}`,
+		`{"$id": "https://example.com/team.json", "items": {"$ref": "user.json"}}`,
+		`{"$id": "https://example.com/other.json", "type": "integer"}`,
+		`{"$id": "https://example.com/waiting.json", "$ref": "missing.json"}`,
+	} {
+		_, err := compiler.Compile([]byte(source))
+		require.NoError(t, err)
+	}
+	require.Contains(t, compiler.unresolvedRefs, "https://example.com/missing.json")
+
+	user, err := compiler.Schema("https://example.com/user.json")
+	require.NoError(t, err)
+
+	compiler.RemoveSchema("https://example.com/name.json")
+	assert.Equal(t, []string{"https://example.com/other.json", "https://example.com/waiting.json"}, cachedURIs(compiler),
+		"schemas referencing the removed one, directly or not, are removed too")
+	assert.False(t, user.Validate(map[string]any{"name": 1}).IsValid(), "removed schemas keep working")
+
+	compiler.RemoveSchema("https://example.com/waiting.json")
+	assert.NotContains(t, compiler.unresolvedRefs, "https://example.com/missing.json")
+
+	compiler.RemoveSchema("https://example.com/unknown.json")
+	assert.Equal(t, []string{"https://example.com/other.json"}, cachedURIs(compiler))
+}
+
+func TestSchemaCacheLimit(t *testing.T) {
+	loads := 0
+	compiler := NewCompiler().SetSchemaCacheLimit(2)
+	compiler.RegisterLoader("mem", func(url string) (io.ReadCloser, error) {
+		loads++
+		return io.NopCloser(strings.NewReader(`{"type": "string"}`)), nil
+	})
+
+	_, err := compiler.Compile([]byte(`{"$id": "mem://a.json"}`))
+	require.NoError(t, err)
+	_, err = compiler.Compile([]byte(`{"$id": "mem://b.json"}`))
+	require.NoError(t, err)
+	_, err = compiler.Schema("mem://a.json")
+	require.NoError(t, err)
+	_, err = compiler.Compile([]byte(`{"$id": "mem://c.json"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mem://a.json", "mem://c.json"}, cachedURIs(compiler), "the least recently used schema is evicted")
+
+	// An evicted schema is loaded again when referenced.
+	_, err = compiler.Schema("mem://b.json")
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+	assert.Len(t, cachedURIs(compiler), 2)
+
+	// Lowering the limit evicts right away.
+	compiler.SetSchemaCacheLimit(1)
+	assert.Equal(t, []string{"mem://b.json"}, cachedURIs(compiler))
+}
+
+func TestSchemaCacheLimitEvictsDependents(t *testing.T) {
+	compiler := NewCompiler().SetSchemaCacheLimit(2)
+	_, err := compiler.Compile([]byte(`{"$id": "https://example.com/name.json", "type": "string"}`))
+	require.NoError(t, err)
+	user, err := compiler.Compile([]byte(`{"$id": "https://example.com/user.json", "$ref": "name.json"}`))
+	require.NoError(t, err)
+
+	// Evicting name.json, the least recently used, takes user.json along.
+	_, err = compiler.Compile([]byte(`{"$id": "https://example.com/a.json"}`))
+	require.NoError(t, err)
+	_, err = compiler.Compile([]byte(`{"$id": "https://example.com/b.json"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/a.json", "https://example.com/b.json"}, cachedURIs(compiler))
+	assert.False(t, user.Validate(1).IsValid())
+}
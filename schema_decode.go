@@ -26,3 +26,39 @@ func decodeExclusiveBound(keyword string, raw jsontext.Value, target **Rat, lega
 	*target = rat
 	return nil
 }
+
+// decodeType decodes "type", keeping Draft-03 unions that list schemas raw
+// until the dialect is known.
+func decodeType(raw jsontext.Value, target *SchemaType, legacy *jsontext.Value) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, target); err == nil {
+		return nil
+	}
+
+	var union []jsontext.Value
+	if err := json.Unmarshal(raw, &union); err != nil {
+		return ErrInvalidSchemaType
+	}
+	*legacy = append((*legacy)[:0], raw...)
+	return nil
+}
+
+// decodeRequired decodes "required", keeping the Draft-03 boolean form raw
+// until the dialect is known.
+func decodeRequired(raw jsontext.Value, target *[]string, legacy *jsontext.Value) error {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	trimmed := bytes.TrimSpace(raw)
+	if bytes.Equal(trimmed, []byte("true")) || bytes.Equal(trimmed, []byte("false")) {
+		*legacy = append((*legacy)[:0], raw...)
+		return nil
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("required: %w", err)
+	}
+	return nil
+}
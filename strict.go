@@ -0,0 +1,504 @@
+package jsonschema
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// StrictMode selects how Compile treats suspicious schemas, see
+// SetStrictMode.
+type StrictMode int
+
+const (
+	// StrictModeOff accepts schemas as the specification does. It is the default.
+	StrictModeOff StrictMode = iota
+	// StrictModeWarn compiles suspicious schemas and records the findings, see
+	// Schema.StrictViolations.
+	StrictModeWarn
+	// StrictModeError makes Compile fail with a *StrictError.
+	StrictModeError
+)
+
+// StrictRule identifies what strict mode found.
+type StrictRule string
+
+const (
+	// StrictUnknownKeyword is a keyword no dialect defines, often a typo.
+	StrictUnknownKeyword StrictRule = "unknownKeyword"
+	// StrictDialectKeyword is a keyword of another dialect than the schema's.
+	StrictDialectKeyword StrictRule = "dialectKeyword"
+	// StrictForbiddenRequired is a required property additionalProperties
+	// false forbids.
+	StrictForbiddenRequired StrictRule = "forbiddenRequired"
+	// StrictTypeMismatch is a keyword that applies to none of the types
+	// listed by "type".
+	StrictTypeMismatch StrictRule = "typeMismatch"
+	// StrictUnusedDefinition is a definition nothing in the document
+	// references.
+	StrictUnusedDefinition StrictRule = "unusedDefinition"
+)
+
+// StrictViolation is one finding of strict mode.
+type StrictViolation struct {
+	Rule     StrictRule
+	Location string // JSON Pointer to the schema within the document.
+	Keyword  string
+	Message  string
+}
+
+func (v StrictViolation) String() string {
+	return fmt.Sprintf("#%s: %s", v.Location, v.Message)
+}
+
+// StrictError reports the findings that made a strict Compile fail.
+type StrictError struct {
+	Violations []StrictViolation
+}
+
+func (e *StrictError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrStrictMode, strings.Join(messages, "; "))
+}
+
+func (e *StrictError) Unwrap() error {
+	return ErrStrictMode
+}
+
+// SetStrictMode makes Compile and CompileBatch check schemas for mistakes the
+// specification allows, like Ajv's strict mode:
+//
+//   - unknown keywords, which are ignored and usually typos such as
+//     "requried" or "maxlength"; keywords starting with "x-" are extensions
+//     and allowed;
+//   - keywords of another dialect than the schema's, such as "prefixItems"
+//     in a Draft-07 schema or "dependencies" in a 2020-12 schema;
+//   - required properties that additionalProperties false forbids;
+//   - type-specific keywords that apply to none of the types listed by
+//     "type", such as "maxLength" with "type": "integer";
+//   - definitions nothing in the document references, unless the document
+//     holds nothing but definitions or the definition has its own "$id".
+//
+// Documents loaded to resolve references are not checked.
+func (c *Compiler) SetStrictMode(mode StrictMode) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.strictMode = mode
+	return c
+}
+
+// StrictViolations returns the findings of strict mode when schema was
+// compiled with StrictModeWarn.
+func (s *Schema) StrictViolations() []StrictViolation {
+	return s.strictViolations
+}
+
+// checkStrict applies the strict mode of c to schema, compiled from
+// jsonSchema.
+func (c *Compiler) checkStrict(jsonSchema []byte, schema *Schema) error {
+	c.mu.RLock()
+	mode := c.strictMode
+	c.mu.RUnlock()
+	if mode == StrictModeOff {
+		return nil
+	}
+	violations := strictViolations(jsonSchema, schema)
+	if mode == StrictModeWarn {
+		schema.strictViolations = violations
+		return nil
+	}
+	if len(violations) > 0 {
+		return &StrictError{Violations: violations}
+	}
+	return nil
+}
+
+// strictViolations checks the keywords of jsonSchema, then the definitions
+// of its compiled form schema.
+func strictViolations(jsonSchema []byte, schema *Schema) []StrictViolation {
+	checker := &strictChecker{}
+	checker.keywords(jsontext.Value(jsonSchema), "", schema.Dialect())
+	checker.definitions(schema)
+	return checker.violations
+}
+
+type strictChecker struct {
+	violations []StrictViolation
+}
+
+func (k *strictChecker) report(rule StrictRule, location, keyword, format string, args ...any) {
+	k.violations = append(k.violations, StrictViolation{
+		Rule:     rule,
+		Location: location,
+		Keyword:  keyword,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Dialect sets of keywordDialects.
+var (
+	allDialects      = []Dialect{Draft202012, Draft201909, Draft7, Draft6, Draft4, Draft3}
+	sinceDraft4      = []Dialect{Draft202012, Draft201909, Draft7, Draft6, Draft4}
+	sinceDraft6      = []Dialect{Draft202012, Draft201909, Draft7, Draft6}
+	sinceDraft7      = []Dialect{Draft202012, Draft201909, Draft7}
+	sinceDraft201909 = []Dialect{Draft202012, Draft201909}
+	untilDraft201909 = []Dialect{Draft201909, Draft7, Draft6, Draft4, Draft3}
+	// extensionDialects holds the keywords this library implements on top of
+	// every dialect, such as "errorMessage".
+	extensionDialects = []Dialect{Draft202012, Draft201909, Draft7, Draft6, Draft4, Draft3, OpenAPI30}
+)
+
+// keywordDialects lists the dialects defining each keyword.
+var keywordDialects = withOpenAPI30Keywords(map[string][]Dialect{
+	"$schema": allDialects, "$ref": allDialects, "$comment": sinceDraft7,
+	"$id": sinceDraft6, "id": {Draft4, Draft3}, "$anchor": sinceDraft201909,
+	"$defs": sinceDraft201909, "definitions": allDialects, "$vocabulary": sinceDraft201909,
+	"$dynamicRef": {Draft202012}, "$dynamicAnchor": {Draft202012},
+	"$recursiveRef": {Draft201909}, "$recursiveAnchor": {Draft201909},
+
+	"title": allDialects, "description": allDialects, "default": allDialects,
+	"examples": sinceDraft6, "readOnly": sinceDraft7, "writeOnly": sinceDraft7,
+	"deprecated": sinceDraft201909,
+
+	"type": allDialects, "enum": allDialects, "const": sinceDraft6, "format": allDialects,
+	"multipleOf": sinceDraft4, "divisibleBy": {Draft3}, "maximum": allDialects, "minimum": allDialects,
+	"exclusiveMaximum": allDialects, "exclusiveMinimum": allDialects,
+	"maxLength": allDialects, "minLength": allDialects, "pattern": allDialects,
+	"contentEncoding": sinceDraft7, "contentMediaType": sinceDraft7, "contentSchema": sinceDraft201909,
+
+	"items": allDialects, "additionalItems": untilDraft201909, "prefixItems": {Draft202012},
+	"maxItems": allDialects, "minItems": allDialects, "uniqueItems": allDialects,
+	"contains": sinceDraft6, "maxContains": sinceDraft201909, "minContains": sinceDraft201909,
+	"unevaluatedItems": sinceDraft201909,
+
+	"properties": allDialects, "patternProperties": allDialects, "additionalProperties": allDialects,
+	"required": allDialects, "maxProperties": sinceDraft4, "minProperties": sinceDraft4,
+	"propertyNames": sinceDraft6, "dependencies": untilDraft201909,
+	"dependentRequired": sinceDraft201909, "dependentSchemas": sinceDraft201909,
+	"unevaluatedProperties": sinceDraft201909,
+
+	"allOf": sinceDraft4, "anyOf": sinceDraft4, "oneOf": sinceDraft4, "not": sinceDraft4,
+	"extends": {Draft3}, "disallow": {Draft3},
+	"if": sinceDraft7, "then": sinceDraft7, "else": sinceDraft7,
+
+	"errorMessage": extensionDialects,
+})
+
+// openAPI30Keywords lists the keywords of the OpenAPI 3.0 Schema Object.
+var openAPI30Keywords = []string{
+	"$ref", "title", "description", "default", "readOnly", "writeOnly", "deprecated",
+	"type", "enum", "format", "multipleOf", "maximum", "minimum", "exclusiveMaximum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "items", "maxItems", "minItems", "uniqueItems",
+	"properties", "additionalProperties", "required", "maxProperties", "minProperties",
+	"allOf", "anyOf", "oneOf", "not",
+	"nullable", "discriminator", "xml", "externalDocs", "example",
+}
+
+func withOpenAPI30Keywords(keywords map[string][]Dialect) map[string][]Dialect {
+	for _, keyword := range openAPI30Keywords {
+		keywords[keyword] = append(slices.Clip(keywords[keyword]), OpenAPI30)
+	}
+	return keywords
+}
+
+// keywordTypes lists the instance types type-specific keywords apply to.
+var keywordTypes = map[string][]string{
+	"maxLength": {"string"}, "minLength": {"string"}, "pattern": {"string"},
+
+	"multipleOf": {"number", "integer"}, "divisibleBy": {"number", "integer"}, "maximum": {"number", "integer"}, "minimum": {"number", "integer"},
+	"exclusiveMaximum": {"number", "integer"}, "exclusiveMinimum": {"number", "integer"},
+
+	"items": {"array"}, "prefixItems": {"array"}, "additionalItems": {"array"},
+	"maxItems": {"array"}, "minItems": {"array"}, "uniqueItems": {"array"},
+	"contains": {"array"}, "maxContains": {"array"}, "minContains": {"array"},
+	"unevaluatedItems": {"array"},
+
+	"properties": {"object"}, "patternProperties": {"object"}, "additionalProperties": {"object"},
+	"required": {"object"}, "maxProperties": {"object"}, "minProperties": {"object"},
+	"propertyNames": {"object"}, "dependencies": {"object"}, "dependentRequired": {"object"},
+	"dependentSchemas": {"object"}, "unevaluatedProperties": {"object"},
+}
+
+// Keywords whose values hold subschemas, by shape.
+var (
+	schemaKeywords = []string{
+		"not", "if", "then", "else", "items", "additionalItems", "contains", "extends",
+		"additionalProperties", "propertyNames", "unevaluatedItems",
+		"unevaluatedProperties", "contentSchema",
+	}
+	schemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems", "items", "extends"}
+	schemaMapKeywords   = []string{
+		"properties", "patternProperties", "$defs", "definitions",
+		"dependentSchemas", "dependencies",
+	}
+)
+
+var dialectNames = map[Dialect]string{
+	Draft202012: "Draft 2020-12",
+	Draft201909: "Draft 2019-09",
+	Draft7:      "Draft-07",
+	Draft6:      "Draft-06",
+	Draft4:      "Draft-04",
+	Draft3:      "Draft-03",
+	OpenAPI30:   "OpenAPI 3.0",
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// keywords checks the keywords of the schema value at location and its
+// subschemas.
+func (k *strictChecker) keywords(value jsontext.Value, location string, dialect Dialect) {
+	var object map[string]jsontext.Value
+	if value.Kind() != '{' || json.Unmarshal(value, &object) != nil {
+		return
+	}
+	if raw, ok := object["$schema"]; ok {
+		var uri string
+		if json.Unmarshal(raw, &uri) == nil {
+			dialect = dialectFromSchemaURI(uri, dialect)
+		}
+	}
+
+	types := schemaTypes(object["type"])
+	for _, keyword := range slices.Sorted(maps.Keys(object)) {
+		if strings.HasPrefix(keyword, "x-") {
+			continue
+		}
+		dialects, known := keywordDialects[keyword]
+		switch {
+		case !known:
+			if suggestion := suggestKeyword(keyword); suggestion != "" {
+				k.report(StrictUnknownKeyword, location, keyword, "unknown keyword %q, did you mean %q?", keyword, suggestion)
+			} else {
+				k.report(StrictUnknownKeyword, location, keyword, "unknown keyword %q", keyword)
+			}
+			continue
+		case !slices.Contains(dialects, dialect) && dialectNames[dialect] != "":
+			k.report(StrictDialectKeyword, location, keyword, "%q is not a %s keyword", keyword, dialectNames[dialect])
+		}
+
+		if keyword == "required" && object[keyword].Kind() != '[' {
+			continue // The Draft-03 form marks the schema's own property as required.
+		}
+		if applies, ok := keywordTypes[keyword]; ok && len(types) > 0 &&
+			!slices.ContainsFunc(applies, func(t string) bool { return slices.Contains(types, t) }) {
+			k.report(StrictTypeMismatch, location, keyword, "%q applies to %s values, but \"type\" is %s",
+				keyword, strings.Join(applies, " and "), strings.Join(types, ", "))
+		}
+	}
+
+	k.forbiddenRequired(object, location)
+
+	keywordLocation := func(keyword string) string {
+		return location + "/" + pointerEscaper.Replace(keyword)
+	}
+	for _, keyword := range schemaKeywords {
+		if raw, ok := object[keyword]; ok && raw.Kind() != '[' {
+			k.keywords(raw, keywordLocation(keyword), dialect)
+		}
+	}
+	for _, keyword := range schemaArrayKeywords {
+		var elements []jsontext.Value
+		if raw, ok := object[keyword]; ok && raw.Kind() == '[' && json.Unmarshal(raw, &elements) == nil {
+			for i, element := range elements {
+				k.keywords(element, fmt.Sprintf("%s/%d", keywordLocation(keyword), i), dialect)
+			}
+		}
+	}
+	for _, keyword := range schemaMapKeywords {
+		var members map[string]jsontext.Value
+		if raw, ok := object[keyword]; ok && json.Unmarshal(raw, &members) == nil {
+			for _, name := range slices.Sorted(maps.Keys(members)) {
+				k.keywords(members[name], keywordLocation(keyword)+"/"+pointerEscaper.Replace(name), dialect)
+			}
+		}
+	}
+}
+
+// forbiddenRequired reports required properties that neither "properties"
+// nor "patternProperties" allow when additionalProperties is false.
+func (k *strictChecker) forbiddenRequired(object map[string]jsontext.Value, location string) {
+	if raw, ok := object["additionalProperties"]; !ok || !isJSONFalse(raw) {
+		return
+	}
+	var required []string
+	if raw, ok := object["required"]; !ok || json.Unmarshal(raw, &required) != nil {
+		return
+	}
+	var properties, patternProperties map[string]jsontext.Value
+	_ = json.Unmarshal(object["properties"], &properties)
+	_ = json.Unmarshal(object["patternProperties"], &patternProperties)
+
+	var patterns []*regexp.Regexp
+	for pattern := range patternProperties {
+		if re, err := regexp.Compile(pattern); err == nil {
+			patterns = append(patterns, re)
+		}
+	}
+	for _, name := range required {
+		if _, ok := properties[name]; ok {
+			continue
+		}
+		if slices.ContainsFunc(patterns, func(re *regexp.Regexp) bool { return re.MatchString(name) }) {
+			continue
+		}
+		k.report(StrictForbiddenRequired, location, "required",
+			"required property %q is forbidden by additionalProperties false", name)
+	}
+}
+
+// definitions reports the definitions of root and its subschemas that no
+// reference within the document resolves to.
+func (k *strictChecker) definitions(root *Schema) {
+	if root.Boolean != nil || len(root.Defs) == 0 {
+		return
+	}
+	// A document of nothing but definitions is a library for other documents.
+	if object, err := schemaObject(root); err == nil && onlyIdentityKeywords(object) {
+		return
+	}
+
+	used := make(map[*Schema]bool)
+	var collect func(*Schema)
+	collect = func(s *Schema) {
+		for _, resolved := range []*Schema{s.ResolvedRef, s.ResolvedDynamicRef} {
+			for ; resolved != nil; resolved = resolved.parent {
+				used[resolved] = true
+			}
+		}
+		s.walkNestedSchemas(collect)
+	}
+	collect(root)
+
+	var visit func(s *Schema, location string)
+	visit = func(s *Schema, location string) {
+		keyword := "$defs"
+		if s.Dialect().refIgnoresSiblings() {
+			keyword = "definitions"
+		}
+		for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
+			def := s.Defs[name]
+			if def != nil && !used[def] && def.ID == "" && def.DynamicAnchor == "" {
+				k.report(StrictUnusedDefinition, location, keyword, "definition %q is never referenced", name)
+			}
+		}
+		for _, child := range strictChildren(s, location) {
+			visit(child.schema, child.location)
+		}
+	}
+	visit(root, "")
+}
+
+type locatedSchema struct {
+	schema   *Schema
+	location string
+}
+
+// strictChildren returns the subschemas of s with their locations.
+func strictChildren(s *Schema, location string) []locatedSchema {
+	var children []locatedSchema
+	add := func(child *Schema, tokens ...string) {
+		if child == nil {
+			return
+		}
+		path := location
+		for _, token := range tokens {
+			path += "/" + pointerEscaper.Replace(token)
+		}
+		children = append(children, locatedSchema{schema: child, location: path})
+	}
+
+	defsKeyword := "$defs"
+	if s.Dialect().refIgnoresSiblings() {
+		defsKeyword = "definitions"
+	}
+	for keyword, schemas := range map[string]map[string]*Schema{
+		defsKeyword:         s.Defs,
+		"properties":        schemaMap(s.Properties),
+		"patternProperties": schemaMap(s.PatternProperties),
+		"dependentSchemas":  s.DependentSchemas,
+	} {
+		for _, name := range slices.Sorted(maps.Keys(schemas)) {
+			add(schemas[name], keyword, name)
+		}
+	}
+	for keyword, schemas := range map[string][]*Schema{
+		"allOf": s.AllOf, "anyOf": s.AnyOf, "oneOf": s.OneOf, "prefixItems": s.PrefixItems,
+	} {
+		for i, child := range schemas {
+			add(child, keyword, fmt.Sprint(i))
+		}
+	}
+	for keyword, child := range map[string]*Schema{
+		"not": s.Not, "if": s.If, "then": s.Then, "else": s.Else, "items": s.Items,
+		"contains": s.Contains, "additionalProperties": s.AdditionalProperties,
+		"propertyNames": s.PropertyNames, "unevaluatedItems": s.UnevaluatedItems,
+		"unevaluatedProperties": s.UnevaluatedProperties, "contentSchema": s.ContentSchema,
+	} {
+		add(child, keyword)
+	}
+	slices.SortFunc(children, func(a, b locatedSchema) int { return strings.Compare(a.location, b.location) })
+	return children
+}
+
+// schemaTypes returns the types listed by a "type" value.
+func schemaTypes(raw jsontext.Value) []string {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return []string{single}
+	}
+	var types []string
+	_ = json.Unmarshal(raw, &types)
+	return types
+}
+
+func isJSONFalse(raw []byte) bool {
+	return strings.TrimSpace(string(raw)) == "false"
+}
+
+// suggestKeyword returns the known keyword an unknown keyword is most likely
+// a misspelling of, or "".
+func suggestKeyword(keyword string) string {
+	best, bestDistance := "", 3
+	if len(keyword) <= 3 {
+		bestDistance = 2
+	}
+	for _, known := range slices.Sorted(maps.Keys(keywordDialects)) {
+		if strings.EqualFold(known, keyword) {
+			return known
+		}
+		if distance := editDistance(strings.ToLower(known), strings.ToLower(keyword)); distance < bestDistance {
+			best, bestDistance = known, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,132 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictModeViolations(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected []StrictViolation
+	}{
+		{
+			name:   "unknown keywords",
+			schema: `{"type": "object", "requried": ["id"], "properties": {"name": {"type": "string", "maxlength": 5}}, "x-internal": true, "widget": "select"}`,
+			expected: []StrictViolation{
+				{Rule: StrictUnknownKeyword, Location: "", Keyword: "requried", Message: `unknown keyword "requried", did you mean "required"?`},
+				{Rule: StrictUnknownKeyword, Location: "", Keyword: "widget", Message: `unknown keyword "widget"`},
+				{Rule: StrictUnknownKeyword, Location: "/properties/name", Keyword: "maxlength", Message: `unknown keyword "maxlength", did you mean "maxLength"?`},
+			},
+		},
+		{
+			name:   "keywords of another dialect",
+			schema: `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "array", "prefixItems": [{"type": "string"}], "items": {"$schema": "https://json-schema.org/draft/2020-12/schema", "dependencies": {}}}`,
+			expected: []StrictViolation{
+				{Rule: StrictDialectKeyword, Location: "", Keyword: "prefixItems", Message: `"prefixItems" is not a Draft-07 keyword`},
+				{Rule: StrictDialectKeyword, Location: "/items", Keyword: "dependencies", Message: `"dependencies" is not a Draft 2020-12 keyword`},
+			},
+		},
+		{
+			name:   "required properties forbidden by additionalProperties",
+			schema: `{"type": "object", "properties": {"id": {}}, "patternProperties": {"^x_": {}}, "additionalProperties": false, "required": ["id", "x_tag", "name"]}`,
+			expected: []StrictViolation{
+				{Rule: StrictForbiddenRequired, Location: "", Keyword: "required", Message: `required property "name" is forbidden by additionalProperties false`},
+			},
+		},
+		{
+			name:   "keywords not matching type",
+			schema: `{"type": "integer", "maxLength": 5, "minimum": 1, "anyOf": [{"type": ["string", "null"], "items": {}, "pattern": "^a"}]}`,
+			expected: []StrictViolation{
+				{Rule: StrictTypeMismatch, Location: "", Keyword: "maxLength", Message: `"maxLength" applies to string values, but "type" is integer`},
+				{Rule: StrictTypeMismatch, Location: "/anyOf/0", Keyword: "items", Message: `"items" applies to array values, but "type" is string, null`},
+			},
+		},
+		{
+			name:   "unused definitions",
+			schema: `{"$ref": "#/$defs/used", "$defs": {"used": {"$ref": "#/$defs/nested/properties/a"}, "nested": {"properties": {"a": {}}}, "unused": {}, "resource": {"$id": "https://example.com/r.json"}}, "properties": {"p": {"$defs": {"inner": {}}}}}`,
+			expected: []StrictViolation{
+				{Rule: StrictUnusedDefinition, Location: "", Keyword: "$defs", Message: `definition "unused" is never referenced`},
+				{Rule: StrictUnusedDefinition, Location: "/properties/p", Keyword: "$defs", Message: `definition "inner" is never referenced`},
+			},
+		},
+		{
+			name:   "definition libraries",
+			schema: `{"$id": "https://example.com/common.json", "$defs": {"id": {"type": "string"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewCompiler().SetStrictMode(StrictModeWarn).Compile([]byte(tt.schema))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schema.StrictViolations())
+		})
+	}
+}
+
+func TestStrictModeError(t *testing.T) {
+	compiler := NewCompiler().SetStrictMode(StrictModeError)
+	_, err := compiler.Compile([]byte(`{"type": "object", "requried": ["id"]}`))
+	require.ErrorIs(t, err, ErrStrictMode)
+	var strictErr *StrictError
+	require.ErrorAs(t, err, &strictErr)
+	require.Len(t, strictErr.Violations, 1)
+	assert.Equal(t, `strict mode violation: #: unknown keyword "requried", did you mean "required"?`, err.Error())
+
+	_, err = compiler.Compile([]byte(`{"type": "object", "required": ["id"]}`))
+	require.NoError(t, err)
+
+	_, err = compiler.CompileBatch(map[string][]byte{
+		"https://example.com/a.json": []byte(`{"type": "string", "minItems": 1}`),
+	})
+	require.ErrorIs(t, err, ErrStrictMode)
+
+	_, err = compiler.Compile([]byte(`{"$id": "https://example.com/typo.json", "requried": ["id"]}`))
+	require.ErrorIs(t, err, ErrStrictMode)
+	_, cached := compiler.cachedSchema("https://example.com/typo.json")
+	assert.False(t, cached, "a rejected schema is not cached")
+	_, cached = compiler.cachedSchema("https://example.com/a.json")
+	assert.False(t, cached, "a rejected batch is not cached")
+
+	compiler = NewCompiler()
+	valid, err := compiler.Compile([]byte(`{"$id": "https://example.com/a.json", "type": "string"}`))
+	require.NoError(t, err)
+	lax, err := compiler.Compile([]byte(`{"$id": "https://example.com/b.json", "minLenght": 3}`))
+	require.NoError(t, err)
+	compiler.SetStrictMode(StrictModeError)
+	schema, err := compiler.Compile([]byte(`{"$id": "https://example.com/a.json", "minLenght": 3}`))
+	require.NoError(t, err, "the cached schema of the $id is checked and returned")
+	assert.Same(t, valid, schema)
+	_, err = compiler.Compile([]byte(`{"$id": "https://example.com/b.json", "minLenght": 3}`))
+	require.ErrorIs(t, err, ErrStrictMode)
+	schema, cached = compiler.cachedSchema("https://example.com/b.json")
+	assert.True(t, cached, "a failed compilation keeps the schemas cached before it")
+	assert.Same(t, lax, schema)
+
+	schema, err = NewCompiler().Compile([]byte(`{"requried": ["id"]}`))
+	require.NoError(t, err, "strict mode is off by default")
+	assert.Empty(t, schema.StrictViolations())
+}
+
+func TestStrictModeErrorMessage(t *testing.T) {
+	for _, dialect := range []Dialect{Draft202012, Draft7, Draft4, OpenAPI30} {
+		compiler := NewCompiler().SetDefaultDialect(dialect).SetStrictMode(StrictModeError)
+		_, err := compiler.Compile([]byte(`{
+			"type": "object",
+			"required": ["id"],
+			"properties": {"id": {"type": "integer", "errorMessage": "id must be an integer"}},
+			"errorMessage": {"required": {"id": "id is required"}}
+		}`))
+		assert.NoError(t, err, "errorMessage is known in %s", dialect)
+	}
+}
+
+func TestSuggestKeyword(t *testing.T) {
+	assert.Equal(t, "required", suggestKeyword("requried"))
+	assert.Equal(t, "additionalProperties", suggestKeyword("additionalproperties"))
+	assert.Equal(t, "minimum", suggestKeyword("minimun"))
+	assert.Empty(t, suggestKeyword("widget"))
+}
@@ -390,17 +390,15 @@ func evaluatePropertyCountStruct(schema *Schema, structValue reflect.Value, fiel
 	}
 
 	if schema.MaxProperties != nil && float64(actualCount) > *schema.MaxProperties {
-		return NewEvaluationError("maxProperties", "too_many_properties",
-			"Value should have at most {max_properties} properties", map[string]any{
-				"max_properties": *schema.MaxProperties,
-			})
+		return newError("maxProperties", CodeTooManyProperties, map[string]any{
+			"max_properties": *schema.MaxProperties,
+		})
 	}
 
 	if schema.MinProperties != nil && float64(actualCount) < *schema.MinProperties {
-		return NewEvaluationError("minProperties", "too_few_properties",
-			"Value should have at least {min_properties} properties", map[string]any{
-				"min_properties": *schema.MinProperties,
-			})
+		return newError("minProperties", CodeTooFewProperties, map[string]any{
+			"min_properties": *schema.MinProperties,
+		})
 	}
 
 	return nil
@@ -481,10 +479,9 @@ func evaluateAdditionalPropertiesStruct(schema *Schema, structValue reflect.Valu
 
 	if len(invalidProperties) > 0 {
 		return results, createValidationError(
-			"additional_property_mismatch",
 			"additionalProperties",
-			"Additional property {property} does not match the schema",
-			"Additional properties {properties} do not match the schema",
+			CodeAdditionalPropertyMismatch,
+			CodeAdditionalPropertiesMismatch,
 			invalidProperties,
 		)
 	}
@@ -521,10 +518,9 @@ func evaluatePropertyNamesStruct(schema *Schema, structValue reflect.Value, fiel
 
 	if len(invalidProperties) > 0 {
 		return results, createValidationError(
-			"property_name_mismatch",
 			"propertyNames",
-			"Property name {property} does not match the schema",
-			"Property names {properties} do not match the schema",
+			CodePropertyNameMismatch,
+			CodePropertyNamesMismatch,
 			invalidProperties,
 		)
 	}
@@ -558,42 +554,41 @@ func evaluateDependentRequiredStruct(schema *Schema, structValue reflect.Value,
 }
 
 func newDependentRequiredMissingError(requiredProp, propName string) *EvaluationError {
-	return NewEvaluationError("dependentRequired", "dependent_required_missing",
-		"Property {property} is required when {dependent_property} is present", map[string]any{
-			"property":           requiredProp,
-			"dependent_property": propName,
-		})
+	return newError("dependentRequired", CodeDependentRequiredMissing, map[string]any{
+		"property":           requiredProp,
+		"dependent_property": propName,
+	})
 }
 
 func createPatternPropertyValidationError(invalidProperties []string) *EvaluationError {
+	slices.Sort(invalidProperties)
 	quotedProperties := make([]string, len(invalidProperties))
 	for i, prop := range invalidProperties {
 		quotedProperties[i] = "'" + prop + "'"
 	}
 
 	if len(quotedProperties) == 1 {
-		return NewEvaluationError(
-			"properties", "pattern_property_mismatch",
-			"Property {property} does not match the pattern schema",
+		return newError(
+			"properties", CodePatternPropertyMismatch,
 			map[string]any{"property": quotedProperties[0]},
 		)
 	}
-	return NewEvaluationError(
-		"properties", "pattern_properties_mismatch",
-		"Properties {properties} do not match their pattern schemas",
+	return newError(
+		"properties", CodePatternPropertiesMismatch,
 		map[string]any{"properties": strings.Join(quotedProperties, ", ")},
 	)
 }
 
 // createValidationError creates a validation error with proper formatting for single or multiple items
-func createValidationError(errorType, keyword string, singleTemplate, multiTemplate string, invalidItems []string) *EvaluationError {
+func createValidationError(keyword string, singleCode, multiCode ErrorCode, invalidItems []string) *EvaluationError {
 	if len(invalidItems) == 1 {
-		return NewEvaluationError(keyword, errorType, singleTemplate, map[string]any{
+		return newError(keyword, singleCode, map[string]any{
 			"property": invalidItems[0],
 		})
 	}
 	if len(invalidItems) > 1 {
-		return NewEvaluationError(keyword, errorType, multiTemplate, map[string]any{
+		slices.Sort(invalidItems)
+		return newError(keyword, multiCode, map[string]any{
 			"properties": strings.Join(invalidItems, ", "),
 		})
 	}
@@ -603,10 +598,9 @@ func createValidationError(errorType, keyword string, singleTemplate, multiTempl
 // createPropertyValidationError creates a validation error for property validation
 func createPropertyValidationError(invalidProperties []string) *EvaluationError {
 	return createValidationError(
-		"property_mismatch",
 		"properties",
-		"Property {property} does not match the schema",
-		"Properties {properties} do not match their schemas",
+		CodePropertyMismatch,
+		CodePropertiesMismatch,
 		invalidProperties,
 	)
 }
@@ -614,10 +608,9 @@ func createPropertyValidationError(invalidProperties []string) *EvaluationError
 // createRequiredValidationError creates a validation error for required field validation
 func createRequiredValidationError(missingFields []string) *EvaluationError {
 	return createValidationError(
-		"required_missing",
 		"required",
-		"Required property {property} is missing",
-		"Required properties {properties} are missing",
+		CodeMissingRequiredProperty,
+		CodeMissingRequiredProperties,
 		missingFields,
 	)
 }
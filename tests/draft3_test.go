@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+func TestDraft3CoreSuite(t *testing.T) {
+	dir := filepath.Join("..", "testdata", "JSON-Schema-Test-Suite", "tests", "draft3")
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf("Failed to list Draft-03 test files: %v", err)
+	}
+	slices.Sort(files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			testJSONSchemaTestSuiteWithCompiler(t, file, func(compiler *jsonschema.Compiler) {
+				compiler.SetDefaultDialect(jsonschema.Draft3)
+			},
+				// References http://json-schema.org/draft-03/schema, which
+				// needs network access; the built-in meta-schema is covered
+				// by the dialect tests.
+				"remote ref, containing refs itself",
+			)
+		})
+	}
+}
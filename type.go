@@ -35,7 +35,7 @@ func evaluateType(schema *Schema, instance any) *EvaluationError {
 	}
 
 	// If no valid type match is found, generate a EvaluationResult
-	return NewEvaluationError("type", "type_mismatch", "Value is {received} but should be {expected}", map[string]any{
+	return newError("type", CodeTypeMismatch, map[string]any{
 		"expected": strings.Join(schema.Type, ", "), // Expected types
 		"received": instanceType,                    // Actual type of the input data
 	})
@@ -51,7 +51,7 @@ func evaluateUnevaluatedItems(
 			}
 		}
 		if len(unevaluatedIndexes) > 0 {
-			return nil, NewEvaluationError("unevaluatedItems", "unevaluated_items_not_allowed", "Unevaluated items are not allowed at indexes: {indexes}", map[string]any{
+			return nil, newError("unevaluatedItems", CodeUnevaluatedItemsNotAllowed, map[string]any{
 				"indexes": strings.Join(unevaluatedIndexes, ", "),
 			})
 		}
@@ -83,12 +83,12 @@ func evaluateUnevaluatedItems(
 	}
 
 	if len(invalidIndexes) == 1 {
-		return results, NewEvaluationError("unevaluatedItems", "unevaluated_item_mismatch", "Item at index {index} does not match the unevaluatedItems schema", map[string]any{
+		return results, newError("unevaluatedItems", CodeUnevaluatedItemMismatch, map[string]any{
 			"index": invalidIndexes[0],
 		})
 	}
 	if len(invalidIndexes) > 1 {
-		return results, NewEvaluationError("unevaluatedItems", "unevaluated_items_mismatch", "Items at indexes {indexes} do not match the unevaluatedItems schema", map[string]any{
+		return results, newError("unevaluatedItems", CodeUnevaluatedItemsMismatch, map[string]any{
 			"indexes": strings.Join(invalidIndexes, ", "),
 		})
 	}
@@ -2,6 +2,7 @@ package jsonschema
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -51,16 +52,17 @@ func evaluateUnevaluatedProperties(
 	}
 
 	if len(invalidProperties) == 1 {
-		return results, NewEvaluationError("properties", "unevaluated_property_mismatch", "Property {property} does not match the unevaluatedProperties schema", map[string]any{
+		return results, newError("properties", CodeUnevaluatedPropertyMismatch, map[string]any{
 			"property": fmt.Sprintf("'%s'", invalidProperties[0]),
 		})
 	}
 	if len(invalidProperties) > 1 {
+		slices.Sort(invalidProperties)
 		quotedProperties := make([]string, len(invalidProperties))
 		for i, prop := range invalidProperties {
 			quotedProperties[i] = fmt.Sprintf("'%s'", prop)
 		}
-		return results, NewEvaluationError("properties", "unevaluated_properties_mismatch", "Properties {properties} do not match the unevaluatedProperties schema", map[string]any{
+		return results, newError("properties", CodeUnevaluatedPropertiesMismatch, map[string]any{
 			"properties": strings.Join(quotedProperties, ", "),
 		})
 	}
@@ -77,11 +77,10 @@ func evaluateUniqueItems(schema *Schema, data []any) *EvaluationError {
 		if indices := hashes[hashValue]; len(indices) > 0 {
 			for _, j := range indices {
 				if deepEqualJSON(item, data[j]) {
-					return NewEvaluationError("uniqueItems", "unique_items_mismatch",
-						"Array items at indices {index1} and {index2} are not unique", map[string]any{
-							"index1": j,
-							"index2": i,
-						})
+					return newError("uniqueItems", CodeUniqueItemsMismatch, map[string]any{
+						"index1": j,
+						"index2": i,
+					})
 				}
 			}
 		}
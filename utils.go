@@ -134,7 +134,7 @@ func resolveRelativeURI(baseURI, relativeURL string) string {
 		return relativeURL
 	}
 	base, err := url.Parse(baseURI)
-	if err != nil || base.Scheme == "" || base.Host == "" {
+	if err != nil || !isHierarchicalURL(base) {
 		return relativeURL
 	}
 	rel, err := url.Parse(relativeURL)
@@ -147,7 +147,14 @@ func resolveRelativeURI(baseURI, relativeURL string) string {
 // isAbsoluteURI checks if the given URL is absolute.
 func isAbsoluteURI(rawURL string) bool {
 	u, err := url.Parse(rawURL)
-	return err == nil && u.Scheme != "" && u.Host != ""
+	return err == nil && isHierarchicalURL(u)
+}
+
+// isHierarchicalURL reports whether u can serve as a base for relative
+// references: it has a scheme and a host, or is a "file" URL, whose host
+// may be empty.
+func isHierarchicalURL(u *url.URL) bool {
+	return u.Scheme != "" && (u.Host != "" || u.Scheme == "file")
 }
 
 func hasURIScheme(rawURL string) bool {
@@ -174,7 +181,7 @@ func getBaseURI(id string) string {
 	if u.Path != "/" && !strings.HasSuffix(u.Path, "/") {
 		u.Path += "/"
 	}
-	if u.Scheme == "" || u.Host == "" {
+	if !isHierarchicalURL(u) {
 		return ""
 	}
 	return u.String()
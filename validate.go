@@ -29,7 +29,7 @@ func (s *Schema) ValidateJSON(data []byte) *EvaluationResult {
 	err := s.Compiler().jsonDecoder(data, &parsed)
 	if err != nil {
 		result := NewEvaluationResult(s)
-		result.AddError(NewEvaluationError("format", "invalid_json", "Invalid JSON format"))
+		result.AddError(newError("format", CodeInvalidJSON))
 		return result
 	}
 
@@ -79,6 +79,7 @@ func (s *Schema) evaluate(instance any, dynamicScope *DynamicScope) (*Evaluation
 		evaluatedProps := make(map[string]bool)
 		evaluatedItems := make(map[int]bool)
 		s.processBasicValidationWithoutRefs(instance, result, evaluatedProps, evaluatedItems)
+		s.applyErrorMessage(result, instance, dynamicScope)
 		return result, evaluatedProps, evaluatedItems
 	}
 
@@ -106,6 +107,7 @@ func (s *Schema) evaluate(instance any, dynamicScope *DynamicScope) (*Evaluation
 	}
 
 	s.processValidationKeywords(instance, dynamicScope, result, evaluatedProps, evaluatedItems)
+	s.applyErrorMessage(result, instance, dynamicScope)
 
 	return result, evaluatedProps, evaluatedItems
 }
@@ -130,7 +132,7 @@ type jsonParseError struct{}
 // processReferences handles $ref and $dynamicRef evaluation
 func (s *Schema) processReferences(instance any, dynamicScope *DynamicScope, result *EvaluationResult, evaluatedProps map[string]bool, evaluatedItems map[int]bool) {
 	if _, ok := instance.(*jsonParseError); ok {
-		result.AddError(NewEvaluationError("format", "invalid_json", "Invalid JSON format in byte array"))
+		result.AddError(newError("format", CodeInvalidJSON))
 		return
 	}
 
@@ -139,7 +141,7 @@ func (s *Schema) processReferences(instance any, dynamicScope *DynamicScope, res
 		if refResult != nil {
 			result.AddDetail(refResult)
 			if !refResult.IsValid() {
-				result.AddError(NewEvaluationError("$ref", "ref_mismatch", "Value does not match the reference schema"))
+				result.AddError(newError("$ref", CodeRefMismatch))
 			}
 		}
 		mergeStringMaps(evaluatedProps, props)
@@ -168,7 +170,7 @@ func (s *Schema) processDynamicRef(instance any, dynamicScope *DynamicScope, res
 	if dynamicRefResult != nil {
 		result.AddDetail(dynamicRefResult)
 		if !dynamicRefResult.IsValid() {
-			result.AddError(NewEvaluationError("$dynamicRef", "dynamic_ref_mismatch", "Value does not match the dynamic reference schema"))
+			result.AddError(newError("$dynamicRef", CodeDynamicRefMismatch))
 		}
 	}
 
@@ -419,7 +421,7 @@ func (s *Schema) evaluateBoolean(instance any, evaluatedProps map[string]bool, e
 		return nil
 	}
 
-	return NewEvaluationError("schema", "false_schema_mismatch", "No values are allowed because the schema is set to 'false'")
+	return newError("schema", CodeFalseSchemaMismatch)
 }
 
 // convertStringMap converts a typed map[string]V to map[string]any.
@@ -546,8 +548,8 @@ func evaluateNumeric(schema *Schema, data any) []*EvaluationError {
 	value := NewRat(data)
 	if value == nil {
 		return []*EvaluationError{
-			NewEvaluationError("type", "invalid_numeric", "Value is {received} but should be numeric", map[string]any{
-				"actual_type": dataType,
+			newError("type", CodeInvalidNumeric, map[string]any{
+				"received": dataType,
 			}),
 		}
 	}
@@ -684,6 +686,7 @@ func validateArrayConstraints(schema *Schema, items []any) []*EvaluationError {
 type DynamicScope struct {
 	schemas      []*Schema // Slice storing pointers to Schema
 	instanceKeys []evaluationInstanceKey
+	root         any // Instance pushed first, referenced by errorMessage templates
 }
 
 // NewDynamicScope creates and returns a new empty DynamicScope.
@@ -696,6 +699,9 @@ func NewDynamicScope() *DynamicScope {
 
 // Push adds a Schema to the dynamic scope.
 func (ds *DynamicScope) Push(schema *Schema, instance ...any) {
+	if len(ds.schemas) == 0 && len(instance) > 0 {
+		ds.root = instance[0]
+	}
 	ds.schemas = append(ds.schemas, schema)
 	if len(instance) > 0 {
 		ds.instanceKeys = append(ds.instanceKeys, newEvaluationInstanceKey(instance[0]))
@@ -724,6 +730,11 @@ func (ds *DynamicScope) Peek() *Schema {
 	return ds.schemas[len(ds.schemas)-1]
 }
 
+// rootInstance returns the instance the outermost schema was applied to.
+func (ds *DynamicScope) rootInstance() any {
+	return ds.root
+}
+
 // IsEmpty checks if the dynamic scope is empty.
 func (ds *DynamicScope) IsEmpty() bool {
 	return len(ds.schemas) == 0
@@ -883,10 +894,9 @@ func (s *Schema) checkAdditionalPropertiesForCircular(object map[string]any, res
 			if allowedProps[prop] {
 				evaluatedProps[prop] = true
 			} else {
-				result.AddError(NewEvaluationError("additionalProperties", "additional_property_false",
-					"Additional property '{property}' not allowed", map[string]any{
-						"property": prop,
-					}))
+				result.AddError(newError("additionalProperties", CodeAdditionalPropertyFalse, map[string]any{
+					"property": prop,
+				}))
 			}
 		}
 		return
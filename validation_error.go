@@ -0,0 +1,200 @@
+package jsonschema
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Error returns the code itself, so codes can be used as sentinels:
+//
+//	errors.Is(result.Err(), jsonschema.CodeTypeMismatch)
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// Is reports whether target is the ErrorCode of this error.
+func (e *EvaluationError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && e.Code == string(code)
+}
+
+// ValidationError is the error form of a failed [EvaluationResult]. It
+// unwraps to the leaf *EvaluationError values of the result, each carrying
+// the instance and schema location it was reported at.
+type ValidationError struct {
+	// Result is the evaluation result the error was built from.
+	Result *EvaluationResult
+
+	leaves []*EvaluationError
+}
+
+// Err returns nil when the result is valid and a *ValidationError otherwise.
+func (e *EvaluationResult) Err() error {
+	if e == nil || e.Valid {
+		return nil
+	}
+	return &ValidationError{Result: e, leaves: e.leafErrors()}
+}
+
+// Error joins the leaf messages, each prefixed with its instance location.
+func (e *ValidationError) Error() string {
+	if len(e.leaves) == 0 {
+		return "validation failed"
+	}
+	var b strings.Builder
+	b.WriteString("validation failed: ")
+	for i, leaf := range e.leaves {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		if leaf.InstanceLocation != "" {
+			b.WriteString(leaf.InstanceLocation)
+			b.WriteString(": ")
+		}
+		b.WriteString(leaf.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the leaf errors for errors.Is and errors.As.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.leaves))
+	for i, leaf := range e.leaves {
+		errs[i] = leaf
+	}
+	return errs
+}
+
+// Errors returns the leaf errors in document order.
+func (e *ValidationError) Errors() []*EvaluationError {
+	return slices.Clone(e.leaves)
+}
+
+// leafErrors collects the errors that are not summaries of failed child
+// results. A summary such as "properties" is replaced by the errors of the
+// children it reports on, unless none of them carry an error of their own.
+// The errors are sorted by instance location in document order, then by
+// evaluation path and keyword.
+func (e *EvaluationResult) leafErrors() []*EvaluationError {
+	var leaves []*EvaluationError
+	e.collectLeafErrors(&leaves, "", "")
+	slices.SortStableFunc(leaves, compareLeafErrors)
+	return leaves
+}
+
+func (e *EvaluationResult) collectLeafErrors(leaves *[]*EvaluationError, baseInstance, basePath string) {
+	instanceLocation := baseInstance
+	if e.InstanceLocation != "" {
+		instanceLocation += escapeInstanceLocation(e.InstanceLocation)
+	}
+	evaluationPath := basePath + e.EvaluationPath
+	schemaLocation := e.SchemaLocation
+	if schemaLocation == "" && e.schema != nil {
+		schemaLocation = e.schema.SchemaLocation("")
+	}
+
+	if len(e.Errors) == 0 {
+		// Summary errors replaced by custom messages leave the failed
+		// children as the only explanation.
+		for _, detail := range e.Details {
+			if !detail.Valid {
+				detail.collectLeafErrors(leaves, instanceLocation, evaluationPath)
+			}
+		}
+		return
+	}
+
+	for _, keyword := range slices.Sorted(maps.Keys(e.Errors)) {
+		before := len(*leaves)
+		for _, detail := range e.Details {
+			if !detail.Valid && reportsOn(keyword, detail.EvaluationPath) {
+				detail.collectLeafErrors(leaves, instanceLocation, evaluationPath)
+			}
+		}
+		if len(*leaves) > before {
+			continue
+		}
+		leaf := *e.Errors[keyword]
+		leaf.EvaluationPath = evaluationPath + "/" + keyword
+		leaf.InstanceLocation = instanceLocation
+		leaf.SchemaLocation = schemaLocation
+		*leaves = append(*leaves, &leaf)
+	}
+}
+
+// summaryKeywords maps error keywords to the keywords whose child results
+// they summarize, where the two differ.
+var summaryKeywords = map[string][]string{
+	"properties":  {"properties", "patternProperties", "unevaluatedProperties"},
+	"minContains": nil,
+	"maxContains": nil,
+}
+
+// reportsOn reports whether an error for keyword summarizes the child result
+// at evaluationPath. Results of referenced schemas are added with an empty
+// evaluation path.
+func reportsOn(keyword, evaluationPath string) bool {
+	if evaluationPath == "" {
+		return keyword == "$ref" || keyword == "$dynamicRef"
+	}
+	keywords, ok := summaryKeywords[keyword]
+	if !ok {
+		keywords = []string{keyword}
+	}
+	for _, k := range keywords {
+		if evaluationPath == "/"+k || strings.HasPrefix(evaluationPath, "/"+k+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// compareLeafErrors orders errors by instance location, comparing array
+// indexes numerically, then by evaluation path and keyword.
+func compareLeafErrors(a, b *EvaluationError) int {
+	if c := comparePointers(a.InstanceLocation, b.InstanceLocation); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.EvaluationPath, b.EvaluationPath); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Keyword, b.Keyword)
+}
+
+// comparePointers compares JSON Pointers token by token. A pointer sorts
+// before the pointers below it.
+func comparePointers(a, b string) int {
+	ta := strings.Split(a, "/")
+	tb := strings.Split(b, "/")
+	for i := 0; i < len(ta) && i < len(tb); i++ {
+		if c := compareTokens(ta[i], tb[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(ta), len(tb))
+}
+
+func compareTokens(a, b string) int {
+	if isArrayIndex(a) && isArrayIndex(b) {
+		if c := cmp.Compare(len(a), len(b)); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(tokenUnescaper.Replace(a), tokenUnescaper.Replace(b))
+}
+
+var tokenUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func isArrayIndex(token string) bool {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
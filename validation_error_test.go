@@ -0,0 +1,119 @@
+package jsonschema
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrReturnsNilForValidResult(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"type": "string"}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, schema.Validate("ok").Err())
+}
+
+func TestErrUnwrapsLeafErrors(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$id": "https://example.com/user",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 3},
+			"address": {
+				"type": "object",
+				"properties": {"zip": {"type": "string"}}
+			}
+		},
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+
+	err = schema.Validate(map[string]any{
+		"name":    "Al",
+		"address": map[string]any{"zip": 12345},
+	}).Err()
+	require.Error(t, err)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.False(t, validationErr.Result.IsValid())
+
+	leaves := validationErr.Errors()
+	require.Len(t, leaves, 3)
+	locations := make(map[string]string, len(leaves))
+	for _, leaf := range leaves {
+		locations[leaf.Code] = leaf.InstanceLocation
+		assert.NotEmpty(t, leaf.SchemaLocation)
+	}
+	assert.Equal(t, map[string]string{
+		"missing_required_property": "",
+		"string_too_short":          "/name",
+		"type_mismatch":             "/address/zip",
+	}, locations)
+
+	assert.ErrorIs(t, err, CodeTypeMismatch)
+	assert.ErrorIs(t, err, CodeStringTooShort)
+	assert.NotErrorIs(t, err, CodePropertiesMismatch, "summary errors are replaced by their causes")
+
+	var evalErr *EvaluationError
+	require.ErrorAs(t, err, &evalErr)
+	assert.Contains(t, []string{"required", "minLength", "type"}, evalErr.Keyword)
+
+	assert.Contains(t, err.Error(), "/address/zip: Value is integer but should be string")
+}
+
+func TestErrReportsCustomMessages(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"properties": {"postcode": {"pattern": "^[0-9]{5}$"}},
+		"errorMessage": {"properties": {"postcode": "Invalid postcode"}}
+	}`))
+	require.NoError(t, err)
+
+	err = schema.Validate(map[string]any{"postcode": "abc"}).Err()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, CodeErrorMessage)
+	assert.Equal(t, "validation failed: /postcode: Invalid postcode", err.Error())
+}
+
+func TestErrJoinsWithOtherErrors(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"anyOf": [{"type": "string"}, {"type": "integer", "minimum": 10}]}`))
+	require.NoError(t, err)
+
+	joined := errors.Join(io.EOF, schema.Validate(3).Err())
+	assert.ErrorIs(t, joined, io.EOF)
+	assert.ErrorIs(t, joined, CodeTypeMismatch)
+	assert.ErrorIs(t, joined, CodeValueBelowMinimum)
+	assert.NotErrorIs(t, joined, CodeAnyOfItemMismatch)
+}
+
+func TestErrUnwrapsReferencedSchemas(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$id": "https://example.com/tree",
+		"$dynamicAnchor": "node",
+		"properties": {
+			"id": {"$ref": "#/$defs/id"},
+			"children": {"items": {"$dynamicRef": "#node"}}
+		},
+		"$defs": {"id": {"type": "integer", "minimum": 1}}
+	}`))
+	require.NoError(t, err)
+
+	err = schema.Validate(map[string]any{
+		"id":       0,
+		"children": []any{map[string]any{"id": "x"}},
+	}).Err()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	keywords := make(map[string]string)
+	for _, leaf := range validationErr.Errors() {
+		keywords[leaf.InstanceLocation] = leaf.Keyword
+	}
+	assert.Equal(t, map[string]string{
+		"/id":            "minimum",
+		"/children/0/id": "type",
+	}, keywords, "errors of referenced schemas replace the $ref and $dynamicRef errors")
+}
@@ -0,0 +1,363 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+)
+
+// CompileYAML compiles a schema authored in YAML, see Compile. The document
+// is converted to JSON first: numbers keep every digit, and YAML features
+// without a JSON equivalent fail with ErrUnsupportedYAML.
+func (c *Compiler) CompileYAML(yamlSchema []byte, uris ...string) (*Schema, error) {
+	jsonSchema, err := yamlToJSON(yamlSchema)
+	if err != nil {
+		return nil, err
+	}
+	return c.Compile(jsonSchema, uris...)
+}
+
+// YAMLToJSON converts a YAML document to JSON the way CompileYAML does, for
+// documents that embed schemas, such as OpenAPI descriptions.
+func YAMLToJSON(yamlDocument []byte) ([]byte, error) {
+	return yamlToJSON(yamlDocument)
+}
+
+// typedBody is a loaded document that knows its media type. Loaders may
+// return any body with a ContentType method, so YAML served from a URL
+// without a .yaml extension is still recognized.
+type typedBody struct {
+	io.ReadCloser
+	contentType string
+}
+
+func (b typedBody) ContentType() string {
+	return b.contentType
+}
+
+// isYAMLDocument reports whether the document loaded from uri is YAML,
+// judging by the media type of body and then by the extension of uri.
+func isYAMLDocument(uri string, body io.Reader) bool {
+	if typed, ok := body.(interface{ ContentType() string }); ok && isYAMLMediaType(typed.ContentType()) {
+		return true
+	}
+	if parsed, err := url.Parse(uri); err == nil {
+		return isYAMLFile(parsed.Path)
+	}
+	return isYAMLFile(uri)
+}
+
+func isYAMLMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	default:
+		return strings.HasSuffix(mediaType, "+yaml")
+	}
+}
+
+func isYAMLFile(path string) bool {
+	return strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+}
+
+// yamlToJSON converts a YAML document to JSON. Numbers are written with
+// their exact digits, plain scalars that YAML 1.2 reads as numbers included,
+// so large integers and long decimals survive like they do in JSON schemas.
+// Mapping keys must be scalars, aliases must not refer to themselves nor
+// expand to more than yamlAliasLimit bytes, and a stream may hold only one
+// document.
+func yamlToJSON(data []byte) ([]byte, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrYAMLUnmarshal, err)
+	}
+
+	var body ast.Node
+	for _, doc := range file.Docs {
+		if doc.Body == nil || doc.Body.Type() == ast.CommentType {
+			continue
+		}
+		if body != nil {
+			return nil, fmt.Errorf("%w: line %d: more than one document", ErrUnsupportedYAML, yamlLine(doc.Body))
+		}
+		body = doc.Body
+	}
+	if body == nil {
+		return nil, fmt.Errorf("%w: empty document", ErrYAMLUnmarshal)
+	}
+
+	converter := &yamlConverter{
+		anchors:   make(map[string]ast.Node),
+		values:    make(map[ast.Node]jsontext.Value),
+		expanding: make(map[ast.Node]bool),
+	}
+	return converter.value(body)
+}
+
+// yamlCoreNumber matches the plain scalars the YAML 1.2 core schema resolves
+// to numbers in decimal notation.
+var yamlCoreNumber = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+
+// yamlAliasLimit bounds the bytes aliases may copy into a converted
+// document. Nested aliases grow exponentially, so a few kilobytes of YAML
+// could otherwise expand to gigabytes of JSON.
+const yamlAliasLimit = 16 << 20
+
+// yamlConverter converts YAML nodes to JSON values. Anchored values are
+// converted once, but every alias still copies its value into the output,
+// which is why the copied bytes are counted.
+type yamlConverter struct {
+	anchors   map[string]ast.Node
+	values    map[ast.Node]jsontext.Value
+	expanding map[ast.Node]bool
+	aliased   int // Bytes copied by aliases so far.
+}
+
+func (c *yamlConverter) value(node ast.Node) (jsontext.Value, error) {
+	switch n := node.(type) {
+	case *ast.NullNode:
+		return jsontext.Value("null"), nil
+	case *ast.BoolNode:
+		return jsontext.Value(strconv.FormatBool(n.Value)), nil
+	case *ast.IntegerNode:
+		return jsontext.Value(fmt.Sprint(n.Value)), nil
+	case *ast.FloatNode:
+		if number, ok := yamlNumber(n.Token.Value); ok {
+			return number, nil
+		}
+		return nil, fmt.Errorf("%w: line %d: %s is not a JSON number", ErrUnsupportedYAML, yamlLine(n), n.Token.Value)
+	case *ast.InfinityNode, *ast.NanNode:
+		return nil, fmt.Errorf("%w: line %d: %s is not a JSON number", ErrUnsupportedYAML, yamlLine(n), n.GetToken().Value)
+	case *ast.StringNode:
+		if n.Token.Type == token.StringType && yamlCoreNumber.MatchString(n.Value) {
+			if number, ok := yamlNumber(n.Value); ok {
+				return number, nil
+			}
+		}
+		return json.Marshal(n.Value)
+	case *ast.LiteralNode:
+		return json.Marshal(n.Value.Value)
+	case *ast.TagNode:
+		if n.Start.Value == "!!str" {
+			if text, ok := yamlScalarText(n.Value); ok {
+				return json.Marshal(text)
+			}
+		}
+		return c.value(n.Value)
+	case *ast.AnchorNode:
+		name := n.Name.GetToken().Value
+		c.anchors[name] = n.Value
+		return c.resolve(name, n.Value)
+	case *ast.AliasNode:
+		name := n.Value.GetToken().Value
+		target, ok := c.anchors[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d: unknown alias %q", ErrYAMLUnmarshal, yamlLine(n), name)
+		}
+		value, err := c.resolve(name, target)
+		if err != nil {
+			return nil, err
+		}
+		if c.aliased += len(value); c.aliased > yamlAliasLimit {
+			return nil, fmt.Errorf("%w: line %d: aliases expand to more than %d bytes", ErrUnsupportedYAML, yamlLine(n), yamlAliasLimit)
+		}
+		return value, nil
+	case *ast.SequenceNode:
+		values := make([]jsontext.Value, len(n.Values))
+		for i, element := range n.Values {
+			value, err := c.value(element)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return json.Marshal(values)
+	case *ast.MappingNode:
+		return c.mapping(n.Values)
+	case *ast.MappingValueNode:
+		return c.mapping([]*ast.MappingValueNode{n})
+	default:
+		return nil, fmt.Errorf("%w: line %d: %s node", ErrUnsupportedYAML, yamlLine(node), node.Type())
+	}
+}
+
+// resolve converts the value anchored as name, failing when it contains an
+// alias to itself.
+func (c *yamlConverter) resolve(name string, node ast.Node) (jsontext.Value, error) {
+	if c.expanding[node] {
+		return nil, fmt.Errorf("%w: line %d: alias %q refers to itself", ErrUnsupportedYAML, yamlLine(node), name)
+	}
+	if value, ok := c.values[node]; ok {
+		return value, nil
+	}
+	c.expanding[node] = true
+	value, err := c.value(node)
+	delete(c.expanding, node)
+	if err != nil {
+		return nil, err
+	}
+	c.values[node] = value
+	return value, nil
+}
+
+// mapping converts the entries of a mapping to a JSON object in document
+// order. Entries of merge keys ("<<") are added after the explicit ones,
+// which take precedence.
+func (c *yamlConverter) mapping(entries []*ast.MappingValueNode) (jsontext.Value, error) {
+	var keys []string
+	members := make(map[string]jsontext.Value, len(entries))
+	var merges []jsontext.Value
+	for _, entry := range entries {
+		value, err := c.value(entry.Value)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Key.IsMergeKey() {
+			merges = append(merges, value)
+			continue
+		}
+		key, err := c.key(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := members[key]; ok {
+			return nil, fmt.Errorf("%w: line %d: duplicate key %q", ErrYAMLUnmarshal, yamlLine(entry.Key), key)
+		}
+		keys = append(keys, key)
+		members[key] = value
+	}
+
+	for _, merge := range merges {
+		sources := []jsontext.Value{merge}
+		if merge.Kind() == '[' {
+			sources = nil
+			if err := json.Unmarshal(merge, &sources); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+			}
+		}
+		for _, source := range sources {
+			if source.Kind() != '{' {
+				return nil, fmt.Errorf("%w: merge key value must be a mapping", ErrYAMLUnmarshal)
+			}
+			decoder := jsontext.NewDecoder(strings.NewReader(string(source)))
+			if _, err := decoder.ReadToken(); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+			}
+			for decoder.PeekKind() != '}' {
+				token, err := decoder.ReadToken()
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+				}
+				name := token.String()
+				value, err := decoder.ReadValue()
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshal, err)
+				}
+				if _, ok := members[name]; !ok {
+					keys = append(keys, name)
+					members[name] = value.Clone()
+				}
+			}
+		}
+	}
+
+	var buf strings.Builder
+	encoder := jsontext.NewEncoder(&buf)
+	if err := encoder.WriteToken(jsontext.BeginObject); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+	}
+	for _, key := range keys {
+		if err := encoder.WriteToken(jsontext.String(key)); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+		}
+		if err := encoder.WriteValue(members[key]); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+		}
+	}
+	if err := encoder.WriteToken(jsontext.EndObject); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDataEncode, err)
+	}
+	return jsontext.Value(strings.TrimSpace(buf.String())), nil
+}
+
+// key returns the JSON member name of a mapping key. Scalar keys are used as
+// written, so "200:" becomes "200"; collection keys have no JSON equivalent.
+func (c *yamlConverter) key(node ast.Node) (string, error) {
+	switch n := node.(type) {
+	case *ast.MappingKeyNode:
+		return c.key(n.Value)
+	case *ast.TagNode:
+		return c.key(n.Value)
+	case *ast.AnchorNode:
+		c.anchors[n.Name.GetToken().Value] = n.Value
+		return c.key(n.Value)
+	}
+	if text, ok := yamlScalarText(node); ok {
+		return text, nil
+	}
+	return "", fmt.Errorf("%w: line %d: mapping key must be a scalar", ErrUnsupportedYAML, yamlLine(node))
+}
+
+// yamlScalarText returns the text of a scalar node as written.
+func yamlScalarText(node ast.Node) (string, bool) {
+	switch n := node.(type) {
+	case *ast.StringNode:
+		return n.Value, true
+	case *ast.LiteralNode:
+		return n.Value.Value, true
+	case *ast.AliasNode:
+		return "", false
+	case ast.ScalarNode:
+		return n.GetToken().Value, true
+	default:
+		return "", false
+	}
+}
+
+// yamlNumber rewrites a YAML decimal number as a JSON number with the same
+// digits, dropping what JSON does not allow: a "+" sign, "_" separators,
+// leading zeros and a bare decimal point.
+func yamlNumber(text string) (jsontext.Value, bool) {
+	text = strings.ReplaceAll(strings.TrimPrefix(text, "+"), "_", "")
+	sign := ""
+	if rest, ok := strings.CutPrefix(text, "-"); ok {
+		sign, text = "-", rest
+	}
+	mantissa, exponent := text, ""
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		mantissa, exponent = text[:i], text[i:]
+	}
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	if whole = strings.TrimLeft(whole, "0"); whole == "" {
+		whole = "0"
+	}
+
+	number := sign + whole
+	if fraction != "" {
+		number += "." + fraction
+	}
+	value := jsontext.Value(number + exponent)
+	return value, value.IsValid()
+}
+
+// yamlLine returns the line node starts on, or 0 when it is unknown.
+func yamlLine(node ast.Node) int {
+	if tk := node.GetToken(); tk != nil && tk.Position != nil {
+		return tk.Position.Line
+	}
+	return 0
+}
@@ -0,0 +1,149 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileYAML(t *testing.T) {
+	schema, err := NewCompiler().CompileYAML([]byte(`
+$id: https://example.com/order.yaml
+type: object
+properties:
+  id:
+    type: integer
+    maximum: 12345678901234567890123
+  price:
+    type: number
+    multipleOf: 0.01
+required: [id]
+`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.ValidateJSON([]byte(`{"id": 12345678901234567890123, "price": 19.99}`)).IsValid())
+	assert.False(t, schema.ValidateJSON([]byte(`{"id": 12345678901234567890124}`)).IsValid())
+	assert.False(t, schema.ValidateJSON([]byte(`{"id": 1, "price": 19.999}`)).IsValid())
+	assert.False(t, schema.ValidateJSON([]byte(`{}`)).IsValid())
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		json string
+	}{
+		{"integers", "a: 0x1F\nb: 1_000\nc: +1\nd: -0\n", `{"a": 31, "b": 1000, "c": 1, "d": 0}`},
+		{"exact numbers", "a: 12345678901234567890123\nb: 1.10\nc: .5\nd: 1e3\ne: -1.5E-3\nf: 007.5\n",
+			`{"a": 12345678901234567890123, "b": 1.10, "c": 0.5, "d": 1e3, "e": -1.5E-3, "f": 7.5}`},
+		{"strings", "a: '1'\nb: !!str 12\nc: |\n  text\nd: 1.2.3\n", `{"a": "1", "b": "12", "c": "text\n", "d": "1.2.3"}`},
+		{"scalar keys", "200: ok\ntrue: yes\n", `{"200": "ok", "true": "yes"}`},
+		{"aliases", "base: &base {type: string}\nname: *base\nlist: [*base]\n",
+			`{"base": {"type": "string"}, "name": {"type": "string"}, "list": [{"type": "string"}]}`},
+		{"merge keys", "base: &base {type: string, minLength: 1}\nname:\n  <<: *base\n  minLength: 2\n",
+			`{"base": {"type": "string", "minLength": 1}, "name": {"minLength": 2, "type": "string"}}`},
+		{"literals", "a: null\nb: ~\nc: true\nd: [1, x]\n", `{"a": null, "b": null, "c": true, "d": [1, "x"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := yamlToJSON([]byte(tt.yaml))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(converted))
+		})
+	}
+
+	converted, err := yamlToJSON([]byte("a: 12345678901234567890123.000000000000000000001\n"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":12345678901234567890123.000000000000000000001}`, string(converted), "digits are kept as written")
+}
+
+func TestYAMLToJSONUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"self-referencing alias", "a: &x [1, *x]\n"},
+		{"self-referencing mapping", "a: &x\n  b: *x\n"},
+		{"collection key", "? {a: 1}\n: b\n"},
+		{"infinity", "a: .inf\n"},
+		{"not a number", "a: .nan\n"},
+		{"several documents", "a: 1\n---\nb: 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := yamlToJSON([]byte(tt.yaml))
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrYAMLUnmarshal)
+		})
+	}
+
+	_, err := yamlToJSON([]byte("a: &x [1, *x]\n"))
+	require.ErrorIs(t, err, ErrUnsupportedYAML)
+	_, err = yamlToJSON([]byte("a: *missing\n"))
+	require.ErrorIs(t, err, ErrYAMLUnmarshal)
+	_, err = yamlToJSON([]byte("# only a comment\n"))
+	require.ErrorIs(t, err, ErrYAMLUnmarshal)
+}
+
+func TestYAMLToJSONLimitsAliasExpansion(t *testing.T) {
+	var bomb strings.Builder
+	bomb.WriteString("l0: &l0 [x, x, x, x, x, x, x, x, x]\n")
+	for level := 1; level <= 9; level++ {
+		previous := fmt.Sprintf("*l%d", level-1)
+		fmt.Fprintf(&bomb, "l%d: &l%d [%s]\n", level, level, strings.Repeat(previous+", ", 8)+previous)
+	}
+	_, err := yamlToJSON([]byte(bomb.String()))
+	require.ErrorIs(t, err, ErrUnsupportedYAML)
+	assert.Contains(t, err.Error(), "aliases expand to more than")
+
+	data, err := yamlToJSON([]byte("a: &a {type: string}\nb: *a\nc: *a\n"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": {"type": "string"}, "b": {"type": "string"}, "c": {"type": "string"}}`, string(data))
+}
+
+func TestYAMLReferences(t *testing.T) {
+	compiler := NewCompiler().RegisterFS("https://example.com/schemas/", fstest.MapFS{
+		"name.yaml": {Data: []byte("type: string\nminLength: 2\n")},
+	})
+	schema, err := compiler.Compile([]byte(`{"properties": {"name": {"$ref": "https://example.com/schemas/name.yaml"}}}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(map[string]any{"name": "Al"}).IsValid())
+	assert.False(t, schema.Validate(map[string]any{"name": "A"}).IsValid())
+}
+
+func TestYAMLReferencesByContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		_, _ = io.WriteString(w, "type: integer\nmaximum: 10\n")
+	}))
+	t.Cleanup(server.Close)
+
+	schema, err := NewCompiler().Compile([]byte(`{"$ref": "` + server.URL + `/limit"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(10).IsValid())
+	assert.False(t, schema.Validate(11).IsValid())
+
+	loader := NewCachingLoader(CachingLoaderOptions{Dir: t.TempDir()})
+	for range 2 {
+		compiler := NewCompiler().RegisterLoader("http", loader.Load)
+		schema, err = compiler.Compile([]byte(`{"$ref": "` + server.URL + `/limit"}`))
+		require.NoError(t, err)
+		assert.False(t, schema.Validate(11).IsValid())
+	}
+}
+
+func TestIsYAMLMediaType(t *testing.T) {
+	for _, contentType := range []string{"application/yaml", "application/x-yaml", "text/yaml; charset=utf-8", "application/openapi+yaml"} {
+		assert.True(t, isYAMLMediaType(contentType), contentType)
+	}
+	for _, contentType := range []string{"", "application/json", "text/plain", "yaml"} {
+		assert.False(t, isYAMLMediaType(contentType), contentType)
+	}
+}